@@ -2,8 +2,12 @@ package progresso
 
 // original code from https://github.com/bartmeuris/progressio
 
-import "fmt"
-import "time"
+import (
+	"fmt"
+	"github.com/archer-v/progresso/locale"
+	"strings"
+	"time"
+)
 
 // SecondFormatter represents a duration in seconds
 type SecondFormatter int64
@@ -43,36 +47,40 @@ func (s SecondFormatter) Seconds() int64 {
 	return int64(s) % 60
 }
 
-func addCountString(ostr string, val int64, str string) string {
-	if val == 0 {
-		return ostr
-	}
-	if len(ostr) != 0 {
-		ostr += ", "
-	}
-	if val < 0 {
-		val = -val
-	}
-	if val == 1 {
-		return ostr + "1 " + str
-	}
-	return fmt.Sprintf("%s%d %ss", ostr, val, str)
-}
-
 // String returns the string representation of the SecondFormatter
 // instance, specifying (if applicable): the amount of weeks, days,
 // hours, minutes and seconds it represents
 func (s SecondFormatter) String() string {
-	sret := ""
-	sret = addCountString(sret, s.Weeks(), "week")
-	sret = addCountString(sret, s.Days(), "day")
-	sret = addCountString(sret, s.Hours(), "hour")
-	sret = addCountString(sret, s.Minutes(), "minute")
-	sret = addCountString(sret, s.Seconds(), "second")
-	if len(sret) == 0 {
-		sret = "0 seconds"
-	} else if s < 0 {
-		sret += " ago"
+	return s.StringLocale(locale.English)
+}
+
+// StringLocale returns the string representation of the SecondFormatter
+// instance like String does, using the language of the locale, English is used if the locale is nil
+func (s SecondFormatter) StringLocale(l *locale.Locale) string {
+	if l == nil {
+		l = locale.English
+	}
+	var parts []string
+	for _, c := range []struct {
+		val  int64
+		name string
+	}{
+		{s.Weeks(), "week"},
+		{s.Days(), "day"},
+		{s.Hours(), "hour"},
+		{s.Minutes(), "minute"},
+		{s.Seconds(), "second"},
+	} {
+		if c.val != 0 {
+			parts = append(parts, l.Duration(c.val, c.name))
+		}
+	}
+	if len(parts) == 0 {
+		return l.Duration(0, "second")
+	}
+	sret := strings.Join(parts, ", ")
+	if s < 0 {
+		sret = fmt.Sprintf(l.Word("%s ago"), sret)
 	}
 	return sret
 }
@@ -84,9 +92,29 @@ func FormatDuration(dur time.Duration) string {
 	return SecondFormatter(dur.Seconds()).String()
 }
 
+// FormatDurationLocale returns the string representation of the specified
+// time.Duration like FormatDuration does, using the language of the locale,
+// English is used if the locale is nil
+func FormatDurationLocale(dur time.Duration, l *locale.Locale) string {
+	return SecondFormatter(dur.Seconds()).StringLocale(l)
+}
+
 // FormatSeconds returns a string representing the (if applicable)
 // amount of weeks, days, hours, minutes and seconds the amount of
 // seconds it is passed as a parameter.
 func FormatSeconds(seconds int64) string {
 	return SecondFormatter(seconds).String()
 }
+
+// FormatClock returns the string representation of the specified
+// time.Duration as a clock: "mm:ss" or "h:mm:ss"
+func FormatClock(dur time.Duration) string {
+	if dur < 0 {
+		dur = 0
+	}
+	s := int64(dur.Round(time.Second) / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
@@ -0,0 +1,237 @@
+package progresso
+
+import (
+	"math"
+	"time"
+)
+
+// Estimator calculates the speed of the work and the remaining time.
+// The tracker adds a sample on every update it records, the time of the sample
+// is the active time since the start of the work (the paused time is excluded)
+type Estimator interface {
+	// Reset clears all collected samples
+	Reset()
+	// Add adds the amount of work processed at the given time
+	Add(at time.Duration, processed int64)
+	// Speed returns the speed in units/sec at the given time, or -1 if it's unknown yet
+	Speed(at time.Duration, processed int64) float64
+	// Remaining returns the estimated time remaining to process the total amount of work,
+	// or -1 if it's unknown yet
+	Remaining(at time.Duration, processed, total int64) time.Duration
+}
+
+// remaining returns the time remaining to process the rest of the work at the given speed
+func remaining(speed float64, processed, total int64) time.Duration {
+	if speed <= 0 {
+		return -1
+	}
+	if processed >= total {
+		return 0
+	}
+	return time.Duration((float64(total-processed) / speed) * float64(time.Second))
+}
+
+// SlidingWindowEstimator calculates the speed over the last N samples.
+// The remaining time is calculated from the average speed since the start.
+// It's the default estimator of the tracker
+type SlidingWindowEstimator struct {
+	updatesW []int64         // list of last work updates
+	updatesT []time.Duration // list of last time updates
+	counter  int             // counter of updates
+}
+
+// NewSlidingWindowEstimator creates a new sliding window estimator with the given number of samples
+func NewSlidingWindowEstimator(slots int) *SlidingWindowEstimator {
+	if slots < 1 {
+		slots = 1
+	}
+	return &SlidingWindowEstimator{
+		updatesW: make([]int64, slots),
+		updatesT: make([]time.Duration, slots),
+	}
+}
+
+// Reset clears all collected samples
+func (e *SlidingWindowEstimator) Reset() {
+	for i := range e.updatesW {
+		e.updatesW[i] = 0
+		e.updatesT[i] = 0
+	}
+	e.counter = 0
+}
+
+// Add saves the sample to the current slot
+func (e *SlidingWindowEstimator) Add(at time.Duration, processed int64) {
+	e.updatesW[e.counter%len(e.updatesW)] = processed
+	e.updatesT[e.counter%len(e.updatesT)] = at
+	e.counter++
+}
+
+// Speed returns the average speed of the last N samples,
+// it isn't calculated until the first N samples are collected
+func (e *SlidingWindowEstimator) Speed(at time.Duration, processed int64) float64 {
+	slot := e.counter % len(e.updatesW)
+	if e.counter < len(e.updatesW) || at <= e.updatesT[slot] {
+		return -1
+	}
+	return float64(processed-e.updatesW[slot]) / float64(at-e.updatesT[slot]) * float64(time.Second)
+}
+
+// Remaining returns the remaining time calculated from the average speed since the start
+func (e *SlidingWindowEstimator) Remaining(at time.Duration, processed, total int64) time.Duration {
+	if e.counter < len(e.updatesW) || at <= 0 {
+		return -1
+	}
+	return remaining(float64(processed)/float64(at)*float64(time.Second), processed, total)
+}
+
+// EWMAEstimator calculates the speed as an exponentially weighted moving average
+// of the speeds between samples. The weight of a speed halves every halfLife,
+// so the estimator smooths out bursty workloads like network I/O
+type EWMAEstimator struct {
+	halfLife  time.Duration
+	speed     float64
+	hasSpeed  bool
+	lastT     time.Duration
+	lastW     int64
+	hasSample bool
+}
+
+// NewEWMAEstimator creates a new EWMA estimator with the given half-life of the speed weight
+func NewEWMAEstimator(halfLife time.Duration) *EWMAEstimator {
+	if halfLife <= 0 {
+		halfLife = time.Second
+	}
+	return &EWMAEstimator{halfLife: halfLife}
+}
+
+// Reset clears all collected samples
+func (e *EWMAEstimator) Reset() {
+	*e = EWMAEstimator{halfLife: e.halfLife}
+}
+
+// Add updates the moving average with the speed since the previous sample
+func (e *EWMAEstimator) Add(at time.Duration, processed int64) {
+	if e.hasSample && at > e.lastT {
+		dt := at - e.lastT
+		speed := float64(processed-e.lastW) / float64(dt) * float64(time.Second)
+		if e.hasSpeed {
+			alpha := 1 - math.Exp(-math.Ln2*float64(dt)/float64(e.halfLife))
+			e.speed += alpha * (speed - e.speed)
+		} else {
+			e.speed = speed
+			e.hasSpeed = true
+		}
+	} else if e.hasSample {
+		// several samples at the same time, merge them
+		e.lastW = processed
+		return
+	}
+	e.lastT = at
+	e.lastW = processed
+	e.hasSample = true
+}
+
+// Speed returns the moving average speed
+func (e *EWMAEstimator) Speed(at time.Duration, processed int64) float64 {
+	if !e.hasSpeed {
+		return -1
+	}
+	return e.speed
+}
+
+// Remaining returns the remaining time calculated from the moving average speed
+func (e *EWMAEstimator) Remaining(at time.Duration, processed, total int64) time.Duration {
+	if !e.hasSpeed {
+		return -1
+	}
+	return remaining(e.speed, processed, total)
+}
+
+// RegressionEstimator fits a line to the last N samples with the least squares method.
+// The speed is the slope of the line, the remaining time is the time the line reaches the total.
+// It suits steady workloads like CPU bound work
+type RegressionEstimator struct {
+	updatesW []int64
+	updatesT []time.Duration
+	counter  int
+}
+
+// NewRegressionEstimator creates a new linear regression estimator over the given number of samples
+func NewRegressionEstimator(samples int) *RegressionEstimator {
+	if samples < 2 {
+		samples = 2
+	}
+	return &RegressionEstimator{
+		updatesW: make([]int64, samples),
+		updatesT: make([]time.Duration, samples),
+	}
+}
+
+// Reset clears all collected samples
+func (e *RegressionEstimator) Reset() {
+	e.counter = 0
+}
+
+// Add saves the sample to the current slot
+func (e *RegressionEstimator) Add(at time.Duration, processed int64) {
+	e.updatesW[e.counter%len(e.updatesW)] = processed
+	e.updatesT[e.counter%len(e.updatesT)] = at
+	e.counter++
+}
+
+// fit returns the line coefficients: processed = a + b * seconds
+func (e *RegressionEstimator) fit() (a, b float64, ok bool) {
+	n := e.counter
+	if n > len(e.updatesW) {
+		n = len(e.updatesW)
+	}
+	if n < 2 {
+		return 0, 0, false
+	}
+	var sumT, sumW, sumTT, sumTW float64
+	for i := 0; i < n; i++ {
+		t := e.updatesT[i].Seconds()
+		w := float64(e.updatesW[i])
+		sumT += t
+		sumW += w
+		sumTT += t * t
+		sumTW += t * w
+	}
+	d := float64(n)*sumTT - sumT*sumT
+	if d == 0 {
+		return 0, 0, false
+	}
+	b = (float64(n)*sumTW - sumT*sumW) / d
+	a = (sumW - b*sumT) / float64(n)
+	return a, b, true
+}
+
+// Speed returns the slope of the fitted line
+func (e *RegressionEstimator) Speed(at time.Duration, processed int64) float64 {
+	_, b, ok := e.fit()
+	if !ok {
+		return -1
+	}
+	if b < 0 {
+		return 0
+	}
+	return b
+}
+
+// Remaining returns the time remaining until the fitted line reaches the total
+func (e *RegressionEstimator) Remaining(at time.Duration, processed, total int64) time.Duration {
+	a, b, ok := e.fit()
+	if !ok || b <= 0 {
+		return -1
+	}
+	if processed >= total {
+		return 0
+	}
+	r := time.Duration(((float64(total)-a)/b)*float64(time.Second)) - at
+	if r < 0 {
+		// the work is ahead of the fitted line
+		return remaining(b, processed, total)
+	}
+	return r
+}
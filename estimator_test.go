@@ -0,0 +1,40 @@
+package progresso
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimators(t *testing.T) {
+	tests := []struct {
+		name      string
+		estimator Estimator
+	}{
+		{name: "SlidingWindow", estimator: NewSlidingWindowEstimator(5)},
+		{name: "EWMA", estimator: NewEWMAEstimator(2 * time.Second)},
+		{name: "Regression", estimator: NewRegressionEstimator(5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.estimator
+			if speed := e.Speed(0, 0); speed != -1 {
+				t.Errorf("got speed %v without samples, want -1", speed)
+			}
+			// steady work at 100 units/sec
+			for i := int64(0); i < 10; i++ {
+				e.Add(time.Duration(i)*time.Second, i*100)
+			}
+			at := 9 * time.Second
+			if speed := e.Speed(at, 900); speed < 99.99 || speed > 100.01 {
+				t.Errorf("got speed %v, want 100", speed)
+			}
+			if r := e.Remaining(at, 900, 2000); r < 11*time.Second-time.Millisecond || r > 11*time.Second+time.Millisecond {
+				t.Errorf("got remaining %v, want 11s", r)
+			}
+			e.Reset()
+			if speed := e.Speed(at, 900); speed != -1 {
+				t.Errorf("got speed %v after reset, want -1", speed)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package progresso
+
+import (
+	"context"
+	"github.com/archer-v/progresso/units"
+	"math"
+	"time"
+)
+
+// DefaultFloatScale defines the number of work values per unit of fractional work,
+// so the fractional work is tracked with 0.001 precision by default
+const DefaultFloatScale = 1000
+
+// FloatProgressTracker is a progress tracker accepting fractional amounts of work
+// (percents reported by an external tool, kilometres travelled, seconds of media encoded).
+// The work is tracked as integer values of 1/scale unit, the Progress has the Scale field set,
+// so Progress.String formats fractional values correctly, and Progress.Float converts
+// the work values (Processed, Total, Speed, SpeedAvg) back to fractional ones.
+// The setters of the ProgressTracker are shadowed returning the FloatProgressTracker, so they can be chained
+type FloatProgressTracker struct {
+	*ProgressTracker
+	rem float64 // the remainder of work less than a work value
+}
+
+// NewFloatProgressTracker creates a new progress tracker accepting fractional amounts of work
+func NewFloatProgressTracker() *FloatProgressTracker {
+	p := &FloatProgressTracker{ProgressTracker: NewProgressTracker()}
+	p.scale = DefaultFloatScale
+	return p
+}
+
+// SetScale sets the number of work values per unit of fractional work,
+// it should be set before the tracker is started
+func (p *FloatProgressTracker) SetScale(scale int64) *FloatProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if scale < 1 {
+		scale = 1
+	}
+	p.scale = scale
+	return p
+}
+
+// toValue converts the fractional work to work values, the remainder is accumulated
+func (p *FloatProgressTracker) toValue(progress float64) int64 {
+	v := progress*float64(p.scale) + p.rem
+	whole := math.Floor(v)
+	p.rem = v - whole
+	return int64(whole)
+}
+
+// Increment increments the progress tracker at the given fractional amount of work,
+// see ProgressTracker.Increment
+func (p *FloatProgressTracker) Increment(progress float64, data ...any) Progress {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if progress <= 0 {
+		return p.increment(0, data...)
+	}
+	return p.increment(p.toValue(progress), data...)
+}
+
+// Update updates the tracker with the new fractional progress value,
+// see ProgressTracker.Update
+func (p *FloatProgressTracker) Update(progress float64, data ...any) Progress {
+	p.m.Lock()
+	defer p.m.Unlock()
+	v := int64(math.Round(progress * float64(p.scale)))
+	if v > p.progress {
+		p.rem = 0
+		return p.increment(v-p.progress, data...)
+	}
+	return p.curProgress(data...)
+}
+
+// SetSize sets the total fractional size of the work to be done
+func (p *FloatProgressTracker) SetSize(size float64) *FloatProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if size <= 0 {
+		p.size = -1
+	} else {
+		p.size = int64(math.Round(size * float64(p.scale)))
+	}
+	return p
+}
+
+// SetUpdateGranule sets the fractional size of the granule of work at which to send updates
+func (p *FloatProgressTracker) SetUpdateGranule(granule float64) *FloatProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.updateGranule = int64(math.Round(granule * float64(p.scale)))
+	return p
+}
+
+// Processed returns the fractional amount of work processed
+func (p *FloatProgressTracker) Processed() float64 {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return float64(p.progress) / float64(p.scale)
+}
+
+// SetItems sets the total number of items to be processed like ProgressTracker.SetItems does
+func (p *FloatProgressTracker) SetItems(total int64) *FloatProgressTracker {
+	p.ProgressTracker.SetItems(total)
+	return p
+}
+
+// SetItemsUnit sets the measurement unit of the items counter like ProgressTracker.SetItemsUnit does
+func (p *FloatProgressTracker) SetItemsUnit(u units.Unit) *FloatProgressTracker {
+	p.ProgressTracker.SetItemsUnit(u)
+	return p
+}
+
+// SetETASource sets the counter the remaining time is calculated from like ProgressTracker.SetETASource does
+func (p *FloatProgressTracker) SetETASource(src ETASource) *FloatProgressTracker {
+	p.ProgressTracker.SetETASource(src)
+	return p
+}
+
+// SetUpdateFreq sets the frequency at which to send updates like ProgressTracker.SetUpdateFreq does
+func (p *FloatProgressTracker) SetUpdateFreq(freq time.Duration) *FloatProgressTracker {
+	p.ProgressTracker.SetUpdateFreq(freq)
+	return p
+}
+
+// SetUpdateGranulePercent sets updates interval in percent of work like ProgressTracker.SetUpdateGranulePercent does
+func (p *FloatProgressTracker) SetUpdateGranulePercent(percent int) *FloatProgressTracker {
+	p.ProgressTracker.SetUpdateGranulePercent(percent)
+	return p
+}
+
+// SetTimeSlots sets the number of time slots like ProgressTracker.SetTimeSlots does
+func (p *FloatProgressTracker) SetTimeSlots(slots int) *FloatProgressTracker {
+	p.ProgressTracker.SetTimeSlots(slots)
+	return p
+}
+
+// SetEstimator sets the speed and remaining time estimator like ProgressTracker.SetEstimator does
+func (p *FloatProgressTracker) SetEstimator(e Estimator) *FloatProgressTracker {
+	p.ProgressTracker.SetEstimator(e)
+	return p
+}
+
+// SetName sets the name of the progress tracker like ProgressTracker.SetName does
+func (p *FloatProgressTracker) SetName(name string) *FloatProgressTracker {
+	p.ProgressTracker.SetName(name)
+	return p
+}
+
+// SetUnit sets the measurement unit of the progress tracker like ProgressTracker.SetUnit does
+func (p *FloatProgressTracker) SetUnit(u units.Unit) *FloatProgressTracker {
+	p.ProgressTracker.SetUnit(u)
+	return p
+}
+
+// SetBlock sets blocking write to the Channel like ProgressTracker.SetBlock does
+func (p *FloatProgressTracker) SetBlock(b bool) *FloatProgressTracker {
+	p.ProgressTracker.SetBlock(b)
+	return p
+}
+
+// SetClock sets the clock used by the tracker like ProgressTracker.SetClock does
+func (p *FloatProgressTracker) SetClock(c Clock) *FloatProgressTracker {
+	p.ProgressTracker.SetClock(c)
+	return p
+}
+
+// OnStart registers the hook called when the tracker records the first update like ProgressTracker.OnStart does
+func (p *FloatProgressTracker) OnStart(f func(Progress)) *FloatProgressTracker {
+	p.ProgressTracker.OnStart(f)
+	return p
+}
+
+// OnUpdate registers the hook called on every update like ProgressTracker.OnUpdate does
+func (p *FloatProgressTracker) OnUpdate(f func(Progress)) *FloatProgressTracker {
+	p.ProgressTracker.OnUpdate(f)
+	return p
+}
+
+// OnFinish registers the hook called with the final update like ProgressTracker.OnFinish does
+func (p *FloatProgressTracker) OnFinish(f func(Progress)) *FloatProgressTracker {
+	p.ProgressTracker.OnFinish(f)
+	return p
+}
+
+// SetHistory enables retaining the history of the updates like ProgressTracker.SetHistory does
+func (p *FloatProgressTracker) SetHistory(size int) *FloatProgressTracker {
+	p.ProgressTracker.SetHistory(size)
+	return p
+}
+
+// OnStall registers the hook called when the tracker becomes stalled like ProgressTracker.OnStall does
+func (p *FloatProgressTracker) OnStall(f func(Progress)) *FloatProgressTracker {
+	p.ProgressTracker.OnStall(f)
+	return p
+}
+
+// SetHeartbeat enables sending the heartbeat updates like ProgressTracker.SetHeartbeat does
+func (p *FloatProgressTracker) SetHeartbeat(enabled bool) *FloatProgressTracker {
+	p.ProgressTracker.SetHeartbeat(enabled)
+	return p
+}
+
+// SetStallTimeout sets the time without new work the tracker is considered stalled after
+// like ProgressTracker.SetStallTimeout does
+func (p *FloatProgressTracker) SetStallTimeout(timeout time.Duration) *FloatProgressTracker {
+	p.ProgressTracker.SetStallTimeout(timeout)
+	return p
+}
+
+// SetContext sets the context stopping the progress tracker like ProgressTracker.SetContext does
+func (p *FloatProgressTracker) SetContext(ctx context.Context) *FloatProgressTracker {
+	p.ProgressTracker.SetContext(ctx)
+	return p
+}
+
+// SetOverflowPolicy sets the policy of sending updates to the Channel like ProgressTracker.SetOverflowPolicy does
+func (p *FloatProgressTracker) SetOverflowPolicy(policy OverflowPolicy) *FloatProgressTracker {
+	p.ProgressTracker.SetOverflowPolicy(policy)
+	return p
+}
+
+// SetBuffer makes the Channel buffered like ProgressTracker.SetBuffer does
+func (p *FloatProgressTracker) SetBuffer(size int) *FloatProgressTracker {
+	p.ProgressTracker.SetBuffer(size)
+	return p
+}
+
+// SetBlockTimeout sets the maximum time to wait for a blocking subscriber like ProgressTracker.SetBlockTimeout does
+func (p *FloatProgressTracker) SetBlockTimeout(timeout time.Duration) *FloatProgressTracker {
+	p.ProgressTracker.SetBlockTimeout(timeout)
+	return p
+}
+
+// Reset resets the progress tracker to an initial state like ProgressTracker.Reset does,
+// the remainder of the fractional work is cleared
+func (p *FloatProgressTracker) Reset() {
+	p.ProgressTracker.Reset()
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.rem = 0
+}
+
+// SetData sets additional customers data to be sent with progress updates like ProgressTracker.SetData does
+func (p *FloatProgressTracker) SetData(d any) *FloatProgressTracker {
+	p.ProgressTracker.SetData(d)
+	return p
+}
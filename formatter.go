@@ -0,0 +1,122 @@
+package progresso
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Formatter formats the Progress with the text/template layout compiled once.
+// The layout is executed with the progress, so all Progress fields are available: {{.Name}}, {{.Percent}}.
+// The helpers are available as the methods:
+//
+//	{{.Format .Processed}} - the work value in human units taking into account the unit and the scale
+//	{{.Bar 20}}            - the bar of 20 cells, empty if the size is unknown
+//	{{.ETA}}               - the estimated clock time the work is finished at, empty if it's unknown
+//	{{.Elapsed}}           - the time the work is performed excluding pauses
+//
+// and the functions:
+//
+//	{{duration .Remaining}} - the duration in words: "1 minute, 5 seconds"
+//	{{clock .Remaining}}    - the duration as a clock: "01:05"
+//	{{percent .Percent}}    - the percentage: "25.00%"
+//
+// For example:
+//
+//	{{.Name}} {{percent .Percent}} {{.Bar 20}} {{.Format .Processed}}/{{.Format .Total}} ETA {{.ETA}}
+type Formatter struct {
+	t     *template.Template
+	clock Clock
+	m     sync.Mutex
+}
+
+// formatData is the data the layout is executed with
+type formatData struct {
+	Progress
+	now time.Time
+}
+
+var formatFuncs = template.FuncMap{
+	"duration": FormatDuration,
+	"clock":    FormatClock,
+	"percent":  func(v float64) string { return fmt.Sprintf("%.2f%%", v) },
+}
+
+// NewFormatter compiles the layout, the error is returned if the layout can't be parsed
+func NewFormatter(layout string) (*Formatter, error) {
+	t, err := template.New("progress").Funcs(formatFuncs).Parse(layout)
+	if err != nil {
+		return nil, err
+	}
+	return &Formatter{t: t, clock: SystemClock}, nil
+}
+
+// SetClock sets the clock the ETA is calculated with, the SystemClock is used by default
+func (f *Formatter) SetClock(c Clock) *Formatter {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.clock = c
+	return f
+}
+
+// Execute writes the formatted progress to the writer
+func (f *Formatter) Execute(w io.Writer, p Progress) error {
+	f.m.Lock()
+	now := f.clock.Now()
+	f.m.Unlock()
+	return f.t.Execute(w, &formatData{Progress: p, now: now})
+}
+
+// Format returns the formatted progress. If the layout fails to execute,
+// the output written until the failure is returned, use Execute to get the error
+func (f *Formatter) Format(p Progress) string {
+	var sb strings.Builder
+	_ = f.Execute(&sb, p)
+	return sb.String()
+}
+
+// Bar returns the bar of the given number of cells: "[=====>    ]", the cells are empty if the size is unknown
+func (d *formatData) Bar(width int) string {
+	n := 0
+	if d.Total > 0 {
+		n = int(d.Percent / 100 * float64(width))
+		if n > width {
+			n = width
+		} else if n < 0 {
+			n = 0
+		}
+	}
+	cells := strings.Repeat("=", n)
+	if n > 0 && n < width {
+		cells = cells[:n-1] + ">"
+	}
+	return "[" + cells + strings.Repeat(" ", width-n) + "]"
+}
+
+// ETA returns the estimated clock time the work is finished at: "15:04:05",
+// the date is added if it's not today. It's empty if the remaining time is unknown
+func (d *formatData) ETA() string {
+	if d.Finished || d.Remaining < 0 {
+		return ""
+	}
+	eta := d.now.Add(d.Remaining)
+	if y, m, day := eta.Date(); y != d.now.Year() || m != d.now.Month() || day != d.now.Day() {
+		return eta.Format("2006-01-02 15:04:05")
+	}
+	return eta.Format("15:04:05")
+}
+
+// Elapsed returns the time the work is performed excluding pauses
+func (d *formatData) Elapsed() time.Duration {
+	end := d.now
+	if d.Finished {
+		end = d.StopTime
+	}
+	if d.StartTime.IsZero() {
+		return 0
+	}
+	return end.Sub(d.StartTime) - d.PausedTime
+}
@@ -0,0 +1,53 @@
+package progresso_test
+
+import (
+	"github.com/archer-v/progresso"
+	"github.com/archer-v/progresso/progressotest"
+	"github.com/archer-v/progresso/units/bytes"
+	"testing"
+	"time"
+)
+
+func TestFormatter(t *testing.T) {
+	c := progressotest.NewClock(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC))
+	f, err := progresso.NewFormatter(
+		`{{.Name}} {{percent .Percent}} {{.Bar 10}} {{.Format .Processed}}/{{.Format .Total}} ` +
+			`{{.Format .Speed}}/s ETA {{.ETA}} ({{clock .Remaining}}, {{duration .Remaining}}) {{clock .Elapsed}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.SetClock(c)
+	p := progresso.Progress{
+		Name:      "file",
+		Processed: 2500,
+		Total:     10000,
+		Percent:   25,
+		Speed:     1000,
+		Unit:      bytes.BytesMetric,
+		Remaining: 75 * time.Second,
+		StartTime: c.Now().Add(-30 * time.Second),
+	}
+	expected := "file 25.00% [=>        ] 2.50kB/10.00kB 1.00kB/s ETA 12:01:15 (01:15, 1 minute, 15 seconds) 00:30"
+	if s := f.Format(p); s != expected {
+		t.Errorf("wrong formatted progress\n%q, expected\n%q", s, expected)
+	}
+
+	eta, _ := progresso.NewFormatter("{{.ETA}}|{{.Bar 4}}")
+	eta.SetClock(c)
+	p.Remaining = 24 * time.Hour
+	if s := eta.Format(p); s != "2020-01-02 12:00:00|[>   ]" {
+		t.Errorf("wrong ETA on another day %q", s)
+	}
+	p.Remaining, p.Total = -1, 0
+	if s := eta.Format(p); s != "|[    ]" {
+		t.Errorf("wrong unknown ETA %q", s)
+	}
+
+	if _, err := progresso.NewFormatter("{{.Name"); err == nil {
+		t.Error("invalid layout is compiled")
+	}
+	bad, _ := progresso.NewFormatter("{{.Name}} {{.Bar}}")
+	if s := bad.Format(p); s != "file " {
+		t.Errorf("wrong output of failed layout %q", s)
+	}
+}
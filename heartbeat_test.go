@@ -0,0 +1,83 @@
+package progresso_test
+
+import (
+	"github.com/archer-v/progresso"
+	"github.com/archer-v/progresso/progressotest"
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, ch <-chan progresso.Progress) progresso.Progress {
+	select {
+	case p := <-ch:
+		return p
+	case <-time.After(5 * time.Second):
+		t.Fatal("the update isn't received")
+	}
+	return progresso.Progress{}
+}
+
+func TestHeartbeatAndStall(t *testing.T) {
+	c := progressotest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	stalls := make(chan progresso.Progress, 1)
+	r := progresso.NewProgressTracker().
+		SetClock(c).
+		SetTimeSlots(2).
+		SetUpdateFreq(time.Second).
+		SetHeartbeat(true).
+		SetStallTimeout(2500 * time.Millisecond).
+		OnStall(func(p progresso.Progress) { stalls <- p })
+	ch := r.Subscribe(10, progresso.DropNewest)
+	defer r.Stop()
+
+	r.Increment(0)
+	receive(t, ch)
+	c.Advance(500 * time.Millisecond)
+	r.Increment(100) // throttled
+
+	c.Advance(500 * time.Millisecond)
+	if p := receive(t, ch); p.Processed != 100 || p.Speed != 100 || p.Stalled {
+		t.Errorf("wrong first heartbeat: %+v", p)
+	}
+	c.Advance(time.Second)
+	if p := receive(t, ch); p.Speed != 0 || p.Stalled {
+		t.Errorf("wrong second heartbeat: %+v", p)
+	}
+	c.Advance(time.Second)
+	if p := receive(t, ch); !p.Stalled {
+		t.Errorf("the update isn't marked as stalled: %+v", p)
+	}
+	if p := receive(t, stalls); !p.Stalled {
+		t.Errorf("wrong stall event: %+v", p)
+	}
+}
+
+func TestStallAfterResume(t *testing.T) {
+	c := progressotest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	stalls := make(chan progresso.Progress, 1)
+	r := progresso.NewProgressTracker().
+		SetClock(c).
+		SetUpdateFreq(time.Second).
+		SetHeartbeat(true).
+		SetStallTimeout(5 * time.Second).
+		OnStall(func(p progresso.Progress) { stalls <- p })
+	ch := r.Subscribe(10, progresso.DropNewest)
+	defer r.Stop()
+
+	r.Increment(100)
+	receive(t, ch)
+	r.Pause()
+	receive(t, ch)
+	c.Advance(time.Minute) // no heartbeats are sent while paused
+	r.Resume()
+	receive(t, ch)
+	c.Advance(time.Second)
+	if p := receive(t, ch); p.Stalled {
+		t.Errorf("the tracker is stalled right after resume: %+v", p)
+	}
+	select {
+	case p := <-stalls:
+		t.Errorf("unexpected stall event: %+v", p)
+	default:
+	}
+}
@@ -0,0 +1,145 @@
+package progresso
+
+import "time"
+
+// Sample is the amount of work processed at the time
+type Sample struct {
+	Time      time.Time `json:"time"`
+	Processed int64     `json:"processed"`
+}
+
+// Throughput is the average speed of the work over the time interval
+type Throughput struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Speed float64   `json:"speed"` // Work/sec over the interval
+}
+
+// SpeedStats is the statistics of the speed over the history
+type SpeedStats struct {
+	Min  float64 `json:"min"`  // The minimum speed between two samples, work/sec
+	Max  float64 `json:"max"`  // The maximum speed between two samples, work/sec
+	Mean float64 `json:"mean"` // The average speed over the whole history, work/sec
+}
+
+// history is the bounded list of samples. When the list is full,
+// every other sample is removed and the minimum interval between samples is doubled,
+// so a long operation is covered entirely with a lower resolution
+type history struct {
+	samples  []Sample
+	size     int
+	interval time.Duration // the minimum interval between samples
+}
+
+func (h *history) add(t time.Time, processed int64) {
+	if h.size <= 0 {
+		return
+	}
+	n := len(h.samples)
+	if n >= 2 && t.Sub(h.samples[n-2].Time) < h.interval {
+		// too close to the previous sample, replace the last one with the latest state
+		h.samples[n-1] = Sample{t, processed}
+		return
+	}
+	if n >= h.size {
+		h.downsample()
+	}
+	h.samples = append(h.samples, Sample{t, processed})
+}
+
+// downsample removes every other sample keeping the first and the last ones
+func (h *history) downsample() {
+	n := len(h.samples)
+	kept := h.samples[:0]
+	for i := 0; i < n; i++ {
+		if i%2 == 0 || i == n-1 {
+			kept = append(kept, h.samples[i])
+		}
+	}
+	h.samples = kept
+	span := h.samples[len(h.samples)-1].Time.Sub(h.samples[0].Time)
+	h.interval *= 2
+	if avg := span / time.Duration(len(h.samples)); avg > h.interval {
+		h.interval = avg
+	}
+}
+
+func (h *history) reset() {
+	h.samples = nil
+	h.interval = 0
+}
+
+// processedAt returns the work processed at the time interpolating between samples
+func (h *history) processedAt(t time.Time) float64 {
+	s := h.samples
+	if !t.After(s[0].Time) {
+		return float64(s[0].Processed)
+	}
+	for i := 1; i < len(s); i++ {
+		if t.After(s[i].Time) {
+			continue
+		}
+		span := s[i].Time.Sub(s[i-1].Time)
+		if span <= 0 {
+			return float64(s[i].Processed)
+		}
+		f := float64(t.Sub(s[i-1].Time)) / float64(span)
+		return float64(s[i-1].Processed) + f*float64(s[i].Processed-s[i-1].Processed)
+	}
+	return float64(s[len(s)-1].Processed)
+}
+
+// throughput returns the speed over the intervals of the given length,
+// or between every two samples if the length is <= 0
+func (h *history) throughput(interval time.Duration) (ret []Throughput) {
+	s := h.samples
+	if len(s) < 2 {
+		return nil
+	}
+	if interval <= 0 {
+		for i := 1; i < len(s); i++ {
+			ret = append(ret, newThroughput(s[i-1].Time, s[i].Time, float64(s[i].Processed-s[i-1].Processed)))
+		}
+		return
+	}
+	end := s[len(s)-1].Time
+	for start := s[0].Time; start.Before(end); start = start.Add(interval) {
+		stop := start.Add(interval)
+		if stop.After(end) {
+			stop = end
+		}
+		ret = append(ret, newThroughput(start, stop, h.processedAt(stop)-h.processedAt(start)))
+	}
+	return
+}
+
+func newThroughput(start, end time.Time, work float64) Throughput {
+	t := Throughput{Start: start, End: end}
+	if d := end.Sub(start); d > 0 {
+		t.Speed = work / d.Seconds()
+	}
+	return t
+}
+
+// stats returns the statistics of the speed between samples
+func (h *history) stats() (st SpeedStats) {
+	tp := h.throughput(0)
+	first := true
+	for _, t := range tp {
+		if !t.End.After(t.Start) {
+			continue
+		}
+		if first || t.Speed < st.Min {
+			st.Min = t.Speed
+		}
+		if first || t.Speed > st.Max {
+			st.Max = t.Speed
+		}
+		first = false
+	}
+	if len(h.samples) >= 2 {
+		s := h.samples
+		st.Mean = newThroughput(s[0].Time, s[len(s)-1].Time, float64(s[len(s)-1].Processed-s[0].Processed)).Speed
+	}
+	return
+}
@@ -0,0 +1,50 @@
+package progresso
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := history{size: 8}
+	// steady work at 100 units/sec
+	for i := 0; i < 20; i++ {
+		h.add(start.Add(time.Duration(i)*time.Second), int64(i)*100)
+	}
+	if len(h.samples) > 8 {
+		t.Errorf("got %d samples, want at most 8", len(h.samples))
+	}
+	if !h.samples[0].Time.Equal(start) || h.samples[len(h.samples)-1].Processed != 1900 {
+		t.Errorf("the history doesn't cover the whole operation: %+v", h.samples)
+	}
+	for _, tp := range h.throughput(0) {
+		if math.Abs(tp.Speed-100) > 1e-9 {
+			t.Errorf("got speed %v between samples, want 100", tp.Speed)
+		}
+	}
+	tp := h.throughput(5 * time.Second)
+	if len(tp) != 4 {
+		t.Fatalf("got %d intervals, want 4", len(tp))
+	}
+	for _, tp := range tp {
+		if math.Abs(tp.Speed-100) > 1e-9 {
+			t.Errorf("got speed %v over the interval %s - %s, want 100", tp.Speed, tp.Start, tp.End)
+		}
+	}
+	if st := h.stats(); math.Abs(st.Min-100) > 1e-9 || math.Abs(st.Max-100) > 1e-9 || math.Abs(st.Mean-100) > 1e-9 {
+		t.Errorf("got speed stats %+v, want 100", st)
+	}
+}
+
+func TestProgressTrackerHistory(t *testing.T) {
+	r := NewProgressTracker().SetSize(100).SetUpdateFreq(0).SetHistory(100)
+	for i := 0; i < 10; i++ {
+		r.Increment(10)
+	}
+	samples := r.History()
+	if len(samples) != 10 || samples[9].Processed != 100 {
+		t.Errorf("got samples %+v, want 10 samples", samples)
+	}
+}
@@ -0,0 +1,44 @@
+package progresso_test
+
+import (
+	"github.com/archer-v/progresso"
+	"github.com/archer-v/progresso/progressotest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestItemsCounter(t *testing.T) {
+	c := progressotest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := progresso.NewBytesProgressTracker().
+		SetClock(c).
+		SetTimeSlots(2).
+		SetUpdateFreq(0).
+		SetSize(1000).
+		SetItems(10).
+		SetETASource(progresso.ETAItems)
+
+	r.Increment(0)
+	c.Advance(time.Second)
+	r.IncrementItems(1)
+	c.Advance(time.Second)
+	r.IncrementItems(1)
+
+	p := r.Progress()
+	if p.Items == nil || p.Items.Processed != 2 || p.Items.Percent != 20 || p.Items.Speed != 1 {
+		t.Fatalf("wrong items progress: %+v", p.Items)
+	}
+	if p.Remaining != 8*time.Second || p.Items.Remaining != 8*time.Second {
+		t.Errorf("got remaining %s and items remaining %s, want 8s", p.Remaining, p.Items.Remaining)
+	}
+	if s := p.String(); !strings.Contains(s, "(Items: 2/10)") {
+		t.Errorf("wrong string representation: %s", s)
+	}
+
+	// the tracker of unknown size is completed when all items are processed
+	r = progresso.NewBytesProgressTracker().SetItems(3)
+	r.Increment(100)
+	if p = r.IncrementItems(3); !p.Completed || p.Items.Processed != 3 {
+		t.Errorf("the progress isn't completed: %+v", p)
+	}
+}
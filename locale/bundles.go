@@ -0,0 +1,191 @@
+package locale
+
+// pluralOneOther is the plural rule of English and German: one for exactly 1, other for the rest
+func pluralOneOther(i int64, v int) Category {
+	if i == 1 && v == 0 {
+		return One
+	}
+	return Other
+}
+
+// pluralRussian is the plural rule of Russian
+func pluralRussian(i int64, v int) Category {
+	if v != 0 {
+		return Other
+	}
+	switch i10, i100 := i%10, i%100; {
+	case i10 == 1 && i100 != 11:
+		return One
+	case i10 >= 2 && i10 <= 4 && (i100 < 12 || i100 > 14):
+		return Few
+	}
+	return Many
+}
+
+// pluralPolish is the plural rule of Polish
+func pluralPolish(i int64, v int) Category {
+	if v != 0 {
+		return Other
+	}
+	switch i10, i100 := i%10, i%100; {
+	case i == 1:
+		return One
+	case i10 >= 2 && i10 <= 4 && (i100 < 12 || i100 > 14):
+		return Few
+	}
+	return Many
+}
+
+// oneOther returns the forms of the word for the one/other plural rule
+func oneOther(one, other string) Forms {
+	return Forms{One: one, Other: other}
+}
+
+// oneFewMany returns the forms of the word for the Russian and Polish plural rules
+func oneFewMany(one, few, many, other string) Forms {
+	return Forms{One: one, Few: few, Many: many, Other: other}
+}
+
+// English is the English locale
+var English = &Locale{
+	Tag:              "en",
+	DecimalSeparator: ".",
+	Plural:           pluralOneOther,
+	Durations: map[string]Forms{
+		"week":   oneOther("week", "weeks"),
+		"day":    oneOther("day", "days"),
+		"hour":   oneOther("hour", "hours"),
+		"minute": oneOther("minute", "minutes"),
+		"second": oneOther("second", "seconds"),
+	},
+	Units: byteForms(func(prefix string) Forms {
+		return oneOther(prefix+"byte", prefix+"bytes")
+	}, map[string]Forms{
+		"metre":     oneOther("metre", "metres"),
+		"kilometre": oneOther("kilometre", "kilometres"),
+	}),
+}
+
+// German is the German locale
+var German = &Locale{
+	Tag:              "de",
+	DecimalSeparator: ",",
+	Plural:           pluralOneOther,
+	Durations: map[string]Forms{
+		"week":   oneOther("Woche", "Wochen"),
+		"day":    oneOther("Tag", "Tage"),
+		"hour":   oneOther("Stunde", "Stunden"),
+		"minute": oneOther("Minute", "Minuten"),
+		"second": oneOther("Sekunde", "Sekunden"),
+	},
+	Units: byteForms(func(prefix string) Forms {
+		return oneOther(capitalize(prefix)+"byte", capitalize(prefix)+"byte")
+	}, map[string]Forms{
+		"byte":      oneOther("Byte", "Byte"),
+		"metre":     oneOther("Meter", "Meter"),
+		"kilometre": oneOther("Kilometer", "Kilometer"),
+		"thousand":  oneOther("Tausend", "Tausend"),
+		"million":   oneOther("Million", "Millionen"),
+		"billion":   oneOther("Milliarde", "Milliarden"),
+	}),
+	Words: map[string]string{
+		"Time":      "Zeit",
+		"Speed":     "Geschwindigkeit",
+		"Speed AVG": "Geschwindigkeit Ø",
+		"AVG":       "Ø",
+		"Remaining": "Verbleibend",
+		"Items":     "Elemente",
+		"%s ago":    "%s zuvor",
+	},
+}
+
+// Russian is the Russian locale
+var Russian = &Locale{
+	Tag:              "ru",
+	DecimalSeparator: ",",
+	Plural:           pluralRussian,
+	Durations: map[string]Forms{
+		"week":   oneFewMany("неделя", "недели", "недель", "недели"),
+		"day":    oneFewMany("день", "дня", "дней", "дня"),
+		"hour":   oneFewMany("час", "часа", "часов", "часа"),
+		"minute": oneFewMany("минута", "минуты", "минут", "минуты"),
+		"second": oneFewMany("секунда", "секунды", "секунд", "секунды"),
+	},
+	Units: byteForms(func(prefix string) Forms {
+		b := russianPrefixes[prefix] + "байт"
+		return oneFewMany(b, b+"а", b, b+"а")
+	}, map[string]Forms{
+		"metre":     oneFewMany("метр", "метра", "метров", "метра"),
+		"kilometre": oneFewMany("километр", "километра", "километров", "километра"),
+		"thousand":  oneFewMany("тысяча", "тысячи", "тысяч", "тысячи"),
+		"million":   oneFewMany("миллион", "миллиона", "миллионов", "миллиона"),
+		"billion":   oneFewMany("миллиард", "миллиарда", "миллиардов", "миллиарда"),
+	}),
+	Words: map[string]string{
+		"Time":      "Время",
+		"Speed":     "Скорость",
+		"Speed AVG": "Средняя скорость",
+		"AVG":       "Средняя",
+		"Remaining": "Осталось",
+		"Items":     "Элементы",
+		"%s ago":    "%s назад",
+	},
+}
+
+// Polish is the Polish locale
+var Polish = &Locale{
+	Tag:              "pl",
+	DecimalSeparator: ",",
+	Plural:           pluralPolish,
+	Durations: map[string]Forms{
+		"week":   oneFewMany("tydzień", "tygodnie", "tygodni", "tygodnia"),
+		"day":    oneFewMany("dzień", "dni", "dni", "dnia"),
+		"hour":   oneFewMany("godzina", "godziny", "godzin", "godziny"),
+		"minute": oneFewMany("minuta", "minuty", "minut", "minuty"),
+		"second": oneFewMany("sekunda", "sekundy", "sekund", "sekundy"),
+	},
+	Units: byteForms(func(prefix string) Forms {
+		b := prefix + "bajt"
+		return oneFewMany(b, b+"y", b+"ów", b+"a")
+	}, map[string]Forms{
+		"metre":     oneFewMany("metr", "metry", "metrów", "metra"),
+		"kilometre": oneFewMany("kilometr", "kilometry", "kilometrów", "kilometra"),
+		"thousand":  oneFewMany("tysiąc", "tysiące", "tysięcy", "tysiąca"),
+		"million":   oneFewMany("milion", "miliony", "milionów", "miliona"),
+		"billion":   oneFewMany("miliard", "miliardy", "miliardów", "miliarda"),
+	}),
+	Words: map[string]string{
+		"Time":      "Czas",
+		"Speed":     "Prędkość",
+		"Speed AVG": "Średnia prędkość",
+		"AVG":       "Średnia",
+		"Remaining": "Pozostało",
+		"Items":     "Elementy",
+		"%s ago":    "%s temu",
+	},
+}
+
+// the prefixes of the metric and IEC byte unit names
+var bytePrefixes = []string{"", "kilo", "mega", "giga", "tera", "peta", "kibi", "mebi", "gibi", "tebi", "pebi"}
+
+var russianPrefixes = map[string]string{
+	"kilo": "кило", "mega": "мега", "giga": "гига", "tera": "тера", "peta": "пета",
+	"kibi": "киби", "mebi": "меби", "gibi": "гиби", "tebi": "теби", "pebi": "пеби",
+}
+
+// byteForms returns the unit forms with the forms of the byte units built by the function
+func byteForms(f func(prefix string) Forms, units map[string]Forms) map[string]Forms {
+	for _, prefix := range bytePrefixes {
+		if _, ok := units[prefix+"byte"]; !ok {
+			units[prefix+"byte"] = f(prefix)
+		}
+	}
+	return units
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return string(s[0]-'a'+'A') + s[1:]
+}
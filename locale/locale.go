@@ -0,0 +1,128 @@
+// Package locale provides the translations, plural rules and number formatting
+// used to localize the durations, the unit names and the progress strings
+package locale
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Category is the CLDR plural category of a number
+type Category int
+
+// The CLDR plural categories
+const (
+	Other Category = iota
+	Zero
+	One
+	Two
+	Few
+	Many
+)
+
+// PluralRule returns the plural category of the number with the integer part i
+// and v visible fraction digits (CLDR plural operands i and v)
+type PluralRule func(i int64, v int) Category
+
+// Forms are the forms of a word by the plural category, the Other form is required
+type Forms map[Category]string
+
+// Locale is the set of translations and formatting rules of a language
+type Locale struct {
+	Tag              string           // The language tag: "en", "de"
+	DecimalSeparator string           // The separator of the fraction digits
+	Plural           PluralRule       // The plural rule of the language
+	Durations        map[string]Forms // The forms of the duration names: "week", "day", "hour", "minute", "second"
+	Units            map[string]Forms // The forms of the unit names by the English name: "byte", "kilobyte"
+	// The translations of the words and patterns by the English text:
+	// "Time", "Speed", "Speed AVG", "AVG", "Remaining", "Items", "%s ago"
+	Words map[string]string
+}
+
+// Form returns the form of the word for the number with the integer part i and v visible fraction digits
+func (l *Locale) Form(forms Forms, i int64, v int) string {
+	if i < 0 {
+		i = -i
+	}
+	if s, ok := forms[l.Plural(i, v)]; ok {
+		return s
+	}
+	return forms[Other]
+}
+
+// Word returns the translation of the word or the word itself if there's no translation
+func (l *Locale) Word(w string) string {
+	if s, ok := l.Words[w]; ok {
+		return s
+	}
+	return w
+}
+
+// Unit returns the form of the unit name for the number with the integer part i and v visible fraction digits,
+// the name itself is returned if there's no translation
+func (l *Locale) Unit(name string, i int64, v int) string {
+	if forms, ok := l.Units[name]; ok {
+		return l.Form(forms, i, v)
+	}
+	return name
+}
+
+// Duration returns the number of duration units: "5 minutes"
+func (l *Locale) Duration(n int64, name string) string {
+	if forms, ok := l.Durations[name]; ok {
+		name = l.Form(forms, n, 0)
+	}
+	if n < 0 {
+		n = -n
+	}
+	return strconv.FormatInt(n, 10) + " " + name
+}
+
+// FormatFloat formats the number with the given number of fraction digits using the decimal separator
+func (l *Locale) FormatFloat(f float64, prec int) string {
+	s := strconv.FormatFloat(f, 'f', prec, 64)
+	if l.DecimalSeparator != "" && l.DecimalSeparator != "." {
+		s = strings.Replace(s, ".", l.DecimalSeparator, 1)
+	}
+	return s
+}
+
+var (
+	locales  = map[string]*Locale{}
+	localesM sync.RWMutex
+)
+
+// Register makes the locale available by its tag with Get, it's safe for concurrent use
+func Register(l *Locale) {
+	localesM.Lock()
+	defer localesM.Unlock()
+	locales[strings.ToLower(l.Tag)] = l
+}
+
+// Get returns the locale by the language tag, "de-AT" and "de_AT.UTF-8" fall back to "de"
+func Get(tag string) (*Locale, bool) {
+	tag = strings.ToLower(tag)
+	if i := strings.IndexByte(tag, '.'); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ReplaceAll(tag, "_", "-")
+	localesM.RLock()
+	defer localesM.RUnlock()
+	for {
+		if l, ok := locales[tag]; ok {
+			return l, true
+		}
+		i := strings.LastIndexByte(tag, '-')
+		if i < 0 {
+			return nil, false
+		}
+		tag = tag[:i]
+	}
+}
+
+func init() {
+	for _, l := range []*Locale{English, German, Russian, Polish} {
+		Register(l)
+	}
+}
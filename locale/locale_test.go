@@ -0,0 +1,74 @@
+package locale
+
+import "testing"
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		l      *Locale
+		i      int64
+		v      int
+		expect Category
+	}{
+		{English, 1, 0, One},
+		{English, 1, 2, Other},
+		{English, 0, 0, Other},
+		{German, 2, 0, Other},
+		{Russian, 1, 0, One},
+		{Russian, 21, 0, One},
+		{Russian, 11, 0, Many},
+		{Russian, 3, 0, Few},
+		{Russian, 13, 0, Many},
+		{Russian, 24, 0, Few},
+		{Russian, 5, 0, Many},
+		{Russian, 2, 2, Other},
+		{Polish, 1, 0, One},
+		{Polish, 21, 0, Many},
+		{Polish, 22, 0, Few},
+		{Polish, 12, 0, Many},
+		{Polish, 0, 0, Many},
+		{Polish, 1, 2, Other},
+	}
+	for _, tt := range tests {
+		if c := tt.l.Plural(tt.i, tt.v); c != tt.expect {
+			t.Errorf("%s plural of %d with %d fraction digits = %d, expected %d", tt.l.Tag, tt.i, tt.v, c, tt.expect)
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	if s := Russian.Duration(3, "hour"); s != "3 часа" {
+		t.Errorf("wrong duration %q", s)
+	}
+	if s := Polish.Unit("kilobyte", 5, 0); s != "kilobajtów" {
+		t.Errorf("wrong unit %q", s)
+	}
+	if s := German.Unit("unknown", 5, 0); s != "unknown" {
+		t.Errorf("unknown unit is translated %q", s)
+	}
+	if s := German.Word("Remaining"); s != "Verbleibend" {
+		t.Errorf("wrong word %q", s)
+	}
+	if s := English.Word("Remaining"); s != "Remaining" {
+		t.Errorf("wrong word %q", s)
+	}
+	if s := German.FormatFloat(1234.5, 2); s != "1234,50" {
+		t.Errorf("wrong decimal separator %q", s)
+	}
+}
+
+func TestGet(t *testing.T) {
+	for tag, expect := range map[string]*Locale{
+		"de":          German,
+		"de-AT":       German,
+		"ru_RU.UTF-8": Russian,
+		"PL":          Polish,
+		"en-US":       English,
+	} {
+		if l, ok := Get(tag); !ok || l != expect {
+			t.Errorf("wrong locale of %q: %v", tag, l)
+		}
+	}
+	if _, ok := Get("fr"); ok {
+		t.Error("unknown locale is found")
+	}
+}
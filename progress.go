@@ -2,57 +2,100 @@ package progresso
 
 import (
 	"fmt"
+	"github.com/archer-v/progresso/locale"
 	"github.com/archer-v/progresso/units"
 	"time"
 )
 
 // Progress is the object sent back over the progress channel.
 type Progress struct {
-	Name        string        `json:"name"`           // The name of the tracker
-	Processed   int64         `json:"processed"`      // The amount of work performed (bytes transferred, for example)
-	Total       int64         `json:"total"`          // Total size of work (bytes to transfer for example). <= 0 if size is unknown.
-	Percent     float64       `json:"percent"`        // If the size is known, the progress of the work in %
-	SpeedAvg    int64         `json:"speed_avg"`      // Work/sec average over the entire work
-	Speed       int64         `json:"speed"`          // Work/sec of the last few works
-	Unit        units.Unit    `json:"unit"`           // The measurement unit system
-	Remaining   time.Duration `json:"remaining"`      // Estimated time remaining, only available if the size is known.
-	RemainingS  int64         `json:"remaining_s" `   // Estimated time remaining in seconds, only available if the size is known.
-	StartTime   time.Time     `json:"start_time"`     // When the work was started
-	EstStopTime time.Time     `json:"est_stop_time" ` // estimated stop time: when the work was stopped
-	StopTime    time.Time     `json:"stop_time"`      // only specified when the work is completed: when the work was stopped
-	Finished    bool          `json:"finished" `      // If the progress was stopped
-	Completed   bool          `json:"completed" `     // If the progress was completed
-	Data        any           `json:"data"`           // An additional user defined data associated with the progress
+	Name        string         `json:"name"`               // The name of the tracker
+	Processed   int64          `json:"processed"`          // The amount of work performed (bytes transferred, for example)
+	Total       int64          `json:"total"`              // Total size of work (bytes to transfer for example). <= 0 if size is unknown.
+	Percent     float64        `json:"percent"`            // If the size is known, the progress of the work in %
+	SpeedAvg    int64          `json:"speed_avg"`          // Work/sec average over the entire work
+	Speed       int64          `json:"speed"`              // Work/sec of the last few works
+	Unit        units.Unit     `json:"unit"`               // The measurement unit system
+	Scale       int64          `json:"scale,omitempty"`    // The number of work values per unit of fractional work, 0 if the work isn't fractional
+	Remaining   time.Duration  `json:"remaining"`          // Estimated time remaining, only available if the size is known.
+	RemainingS  int64          `json:"remaining_s" `       // Estimated time remaining in seconds, only available if the size is known.
+	StartTime   time.Time      `json:"start_time"`         // When the work was started
+	EstStopTime time.Time      `json:"est_stop_time" `     // estimated stop time: when the work was stopped
+	StopTime    time.Time      `json:"stop_time"`          // only specified when the work is completed: when the work was stopped
+	Finished    bool           `json:"finished" `          // If the progress was stopped
+	Completed   bool           `json:"completed" `         // If the progress was completed
+	Cancelled   bool           `json:"cancelled"`          // If the progress was cancelled
+	Failed      bool           `json:"failed"`             // If the progress was failed
+	Err         error          `json:"-"`                  // The error the progress was failed or cancelled with
+	Error       string         `json:"error,omitempty"`    // The text of the Err
+	Dropped     int64          `json:"dropped"`            // The number of updates the receiver of this one has missed
+	Paused      bool           `json:"paused"`             // If the progress is paused
+	PausedTime  time.Duration  `json:"paused_time"`        // The time the progress was paused, it's excluded from the speed and remaining time
+	Stalled     bool           `json:"stalled"`            // If no work has been recorded for the stall timeout
+	Data        any            `json:"data"`               // An additional user defined data associated with the progress
+	Items       *ItemsProgress `json:"items,omitempty"`    // The progress of the items counter if it's used
+	Children    []Progress     `json:"children,omitempty"` // The progress of every child of a ProgressGroup
+}
+
+// ItemsProgress is the progress of the items counter tracked in addition to the work volume
+type ItemsProgress struct {
+	Processed int64         `json:"processed"` // The number of items processed
+	Total     int64         `json:"total"`     // Total number of items, <= 0 if it's unknown
+	Percent   float64       `json:"percent"`   // If the total is known, the progress of the items in %
+	SpeedAvg  float64       `json:"speed_avg"` // Items/sec average over the entire work
+	Speed     float64       `json:"speed"`     // Items/sec of the last few updates
+	Unit      units.Unit    `json:"unit"`      // The measurement unit of the items
+	Remaining time.Duration `json:"remaining"` // Estimated time remaining calculated from the items, only available if the total is known
 }
 
 // String returns a string representation of the progress. It takes into account
 // if the size was known, and only tries to display relevant data.
 func (p *Progress) String() string {
-	timeS := fmt.Sprintf(" (Time: %s", FormatDuration(time.Since(p.StartTime)))
+	return p.StringLocale(locale.English)
+}
+
+// StringLocale returns a string representation of the progress like String does,
+// using the language, the decimal separator and the plural forms of the locale.
+// English is used if the locale is nil
+func (p *Progress) StringLocale(l *locale.Locale) string {
+	if l == nil {
+		l = locale.English
+	}
+	timeS := fmt.Sprintf(" (%s: %s", l.Word("Time"), FormatDurationLocale(time.Since(p.StartTime)-p.PausedTime, l))
 	// Build the Speed string
 	speedS := ""
 	if p.Speed > 0 {
-		speedS = fmt.Sprintf(" (Speed: %s", p.Unit.Format(p.Speed, true)) + "/s"
+		speedS = fmt.Sprintf(" (%s: %s", l.Word("Speed"), p.formatLocale(p.Speed, l)) + "/s"
 	}
 	if p.SpeedAvg > 0 {
 		if len(speedS) > 0 {
-			speedS += " / AVG: "
+			speedS += " / " + l.Word("AVG") + ": "
 		} else {
-			speedS = " (Speed AVG: "
+			speedS = " (" + l.Word("Speed AVG") + ": "
 		}
-		speedS += p.Unit.Format(p.SpeedAvg, true) + "/s"
+		speedS += p.formatLocale(p.SpeedAvg, l) + "/s"
 	}
 	if len(speedS) > 0 {
 		speedS += ")"
 	}
 
+	itemsS := ""
+	if p.Items != nil {
+		itemsS = " (" + l.Word("Items") + ": " + p.Items.Unit.FormatLocale(p.Items.Processed, true, l)
+		if p.Items.Total > 0 {
+			itemsS += "/" + p.Items.Unit.FormatLocale(p.Items.Total, true, l)
+		}
+		itemsS += ")"
+	}
+
 	if p.Total <= 0 {
 		// No size was given, we can only show:
 		// - Amount read/written
 		// - average speed
 		// - current speed
-		return fmt.Sprintf("%s%s%s)",
-			p.Unit.Format(p.Processed, true),
+		return fmt.Sprintf("%s%s%s%s)",
+			p.formatLocale(p.Processed, l),
+			itemsS,
 			speedS,
 			timeS,
 		)
@@ -63,15 +106,39 @@ func (p *Progress) String() string {
 	// - Remaining time
 	timeR := ""
 	if p.Remaining >= time.Duration(0) {
-		timeR = fmt.Sprintf(" / Remaining: %s", FormatDuration(p.Remaining))
+		timeR = fmt.Sprintf(" / %s: %s", l.Word("Remaining"), FormatDurationLocale(p.Remaining, l))
 	}
 
-	return fmt.Sprintf("[%02.2f%%] (%s/%s)%s%s%s)",
-		p.Percent,
-		p.Unit.Format(p.Processed, true),
-		p.Unit.Format(p.Total, true),
+	return fmt.Sprintf("[%s%%] (%s/%s)%s%s%s%s)",
+		l.FormatFloat(p.Percent, 2),
+		p.formatLocale(p.Processed, l),
+		p.formatLocale(p.Total, l),
+		itemsS,
 		speedS,
 		timeS,
 		timeR,
 	)
 }
+
+// Format formats the work value of the progress (Processed, Total, Speed, SpeedAvg)
+// using the progress unit with short names taking into account the scale
+func (p *Progress) Format(v int64) string {
+	return p.formatLocale(v, nil)
+}
+
+// formatLocale formats the work value like Format does using the locale
+func (p *Progress) formatLocale(v int64, l *locale.Locale) string {
+	if p.Scale > 1 {
+		return p.Unit.FormatFloatLocale(p.Float(v), true, l)
+	}
+	return p.Unit.FormatLocale(v, true, l)
+}
+
+// Float converts the work value of the progress (Processed, Total, Speed, SpeedAvg)
+// to the fractional amount of work taking into account the scale
+func (p *Progress) Float(v int64) float64 {
+	if p.Scale > 1 {
+		return float64(v) / float64(p.Scale)
+	}
+	return float64(v)
+}
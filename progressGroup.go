@@ -0,0 +1,326 @@
+package progresso
+
+import (
+	"github.com/archer-v/progresso/units"
+	"sync"
+	"time"
+)
+
+// ProgressGroup is a parent tracker combining the progress of several child trackers.
+// It emits the combined progress over the Channel whenever any child sends an update,
+// the Children field of the combined progress holds the breakdown per child.
+//
+// The work of all children is summed up, so they should use the same unit and scale.
+// Every child contributes to the combined percentage according to its weight.
+// A child with unknown size contributes 0% until it's finished and 100% after that.
+// The finished group is failed or cancelled if any child is, with the error of the first such child.
+type ProgressGroup struct {
+	name      string
+	unit      units.Unit
+	scale     int64
+	data      any
+	clock     Clock
+	Channel   chan Progress
+	main      *subscriber
+	hub       hub
+	children  []*groupChild
+	startTime time.Time
+	finished  bool
+	final     Progress
+	m         sync.Mutex
+}
+
+// groupChild is a child tracker of the group
+type groupChild struct {
+	tracker *ProgressTracker
+	weight  float64  // the weight of the child, <= 0 if the child size is used as a weight
+	last    Progress // the last known progress of the child
+}
+
+// NewProgressGroup creates a new empty progress group
+func NewProgressGroup() (g *ProgressGroup) {
+	g = &ProgressGroup{
+		Channel: make(chan Progress),
+		clock:   SystemClock,
+	}
+	g.hub.clock = g.clock
+	g.main = g.hub.add(g.Channel, DropNewest)
+	return
+}
+
+// Add adds the child tracker to the group. The weight defines the share of the child
+// in the combined percentage, specify a weight <= 0 to use the child size as a weight.
+// A child with unknown size and without a weight gets the average weight of other children.
+// All children should be added before any of them is finished
+func (g *ProgressGroup) Add(t *ProgressTracker, weight float64) *ProgressGroup {
+	ch := t.Subscribe(1, DropNewest)
+	g.m.Lock()
+	defer g.m.Unlock()
+	c := &groupChild{
+		tracker: t,
+		weight:  weight,
+		last:    t.Progress(),
+	}
+	if g.unit.Name == "" {
+		g.unit = c.last.Unit
+	}
+	if len(g.children) == 0 {
+		g.scale = c.last.Scale
+	}
+	g.children = append(g.children, c)
+	go g.watch(c, ch)
+	return g
+}
+
+// watch receives the child updates and sends the combined progress
+func (g *ProgressGroup) watch(c *groupChild, ch <-chan Progress) {
+	for prog := range ch {
+		if !prog.Finished {
+			// take the latest state since the received one can be stale
+			prog = c.tracker.Progress()
+		}
+		g.update(c, prog)
+	}
+}
+
+func (g *ProgressGroup) update(c *groupChild, prog Progress) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	if g.finished {
+		return
+	}
+	c.last = prog
+	combined := g.curProgress()
+	if !combined.Finished {
+		g.hub.send(combined)
+		return
+	}
+	g.finished = true
+	g.final = combined
+	g.hub.sendFinal(combined)
+	g.hub.close()
+	g.Channel = nil
+}
+
+func (g *ProgressGroup) curProgress() (progress Progress) {
+	progress = Progress{
+		Name:     g.name,
+		Unit:     g.unit,
+		Scale:    g.scale,
+		Data:     g.data,
+		Children: make([]Progress, len(g.children)),
+	}
+
+	// Calculate weights of the children
+	weights := make([]float64, len(g.children))
+	var sumW float64
+	var numW int
+	for i, c := range g.children {
+		if c.weight > 0 {
+			weights[i] = c.weight
+		} else if c.last.Total > 0 {
+			weights[i] = float64(c.last.Total)
+		} else {
+			continue
+		}
+		sumW += weights[i]
+		numW++
+	}
+	avgW := 1.0
+	if numW > 0 {
+		avgW = sumW / float64(numW)
+	}
+
+	var done, total float64
+	finished, completed := len(g.children) > 0, len(g.children) > 0
+	failed, cancelled := false, false
+	var err error
+	for i, c := range g.children {
+		last := c.last
+		progress.Children[i] = last
+
+		w := weights[i]
+		if w == 0 {
+			w = avgW
+		}
+		total += w
+		if last.Total > 0 {
+			f := float64(last.Processed) / float64(last.Total)
+			if f > 1 || last.Completed {
+				f = 1
+			}
+			done += w * f
+		} else if last.Finished {
+			done += w
+		}
+
+		progress.Processed += last.Processed
+		if last.Total > 0 && progress.Total >= 0 {
+			progress.Total += last.Total
+		} else {
+			progress.Total = -1
+		}
+		if last.Speed > 0 {
+			progress.Speed += last.Speed
+		}
+		if !last.StartTime.IsZero() && (progress.StartTime.IsZero() || last.StartTime.Before(progress.StartTime)) {
+			progress.StartTime = last.StartTime
+		}
+		if last.StopTime.After(progress.StopTime) {
+			progress.StopTime = last.StopTime
+		}
+		finished = finished && last.Finished
+		completed = completed && last.Completed
+		if last.Failed && !failed {
+			// the error of the first failed child takes precedence over the cancellation
+			err = last.Err
+		} else if err == nil {
+			err = last.Err
+		}
+		failed = failed || last.Failed
+		cancelled = cancelled || last.Cancelled
+	}
+	if total > 0 {
+		progress.Percent = float64(int64(done/total*10000.0)) / 100.0
+	}
+	if g.startTime.IsZero() {
+		g.startTime = progress.StartTime
+	}
+	progress.StartTime = g.startTime
+	progress.Paused, progress.PausedTime = g.pausedTime()
+
+	// Calculate the average speed and the remaining time using the active time only
+	curTime := g.clock.Now()
+	tp := curTime.Sub(progress.StartTime) - progress.PausedTime
+	if progress.StartTime.IsZero() || tp <= 0 {
+		progress.SpeedAvg = -1
+		progress.Speed = -1
+	} else {
+		progress.SpeedAvg = int64((float64(progress.Processed) / float64(tp)) * float64(time.Second))
+	}
+	if progress.SpeedAvg >= 0 && progress.Percent > 0 && progress.Percent < 100 {
+		progress.Remaining = time.Duration(float64(tp) * (100 - progress.Percent) / progress.Percent)
+		progress.RemainingS = int64(progress.Remaining / time.Second)
+		progress.EstStopTime = curTime.Add(progress.Remaining)
+	} else {
+		progress.Remaining = -1
+		progress.RemainingS = -1
+	}
+
+	if finished {
+		progress.Finished = true
+		progress.Completed = completed && !failed && !cancelled
+		progress.Failed = failed
+		progress.Cancelled = cancelled && !failed
+		if err != nil {
+			progress.Err = err
+			progress.Error = err.Error()
+		}
+		progress.Remaining = 0
+		progress.RemainingS = 0
+	} else {
+		progress.StopTime = time.Time{}
+	}
+	return
+}
+
+// pausedTime returns if all running children are paused and the time all of them were paused,
+// that is estimated as the smallest paused time of the running children.
+// The finished children are taken into account only when all children are finished
+func (g *ProgressGroup) pausedTime() (paused bool, pausedTime time.Duration) {
+	running := false
+	for _, c := range g.children {
+		running = running || !c.last.Finished
+	}
+	n := 0
+	for _, c := range g.children {
+		if c.last.StartTime.IsZero() || running && c.last.Finished {
+			continue
+		}
+		if n == 0 || c.last.PausedTime < pausedTime {
+			pausedTime = c.last.PausedTime
+		}
+		paused = (n == 0 || paused) && c.last.Paused
+		n++
+	}
+	return
+}
+
+// Progress returns the current combined progress of the group
+func (g *ProgressGroup) Progress() Progress {
+	g.m.Lock()
+	defer g.m.Unlock()
+	if g.finished {
+		return g.final
+	}
+	return g.curProgress()
+}
+
+// Subscribe creates a new channel receiving the combined progress updates,
+// see ProgressTracker.Subscribe
+func (g *ProgressGroup) Subscribe(buffer int, policy OverflowPolicy) <-chan Progress {
+	g.m.Lock()
+	defer g.m.Unlock()
+	if g.finished {
+		ch := make(chan Progress, 1)
+		ch <- g.final
+		close(ch)
+		return ch
+	}
+	return g.hub.subscribe(buffer, policy).ch
+}
+
+// Unsubscribe stops sending the updates to the channel returned by Subscribe and closes it
+func (g *ProgressGroup) Unsubscribe(ch <-chan Progress) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	if s := g.hub.remove(ch); s != nil && s == g.main {
+		g.Channel = nil
+	}
+}
+
+// SetName sets the name of the progress group
+func (g *ProgressGroup) SetName(name string) *ProgressGroup {
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.name = name
+	return g
+}
+
+// SetUnit sets the measurement unit of the combined progress,
+// by default the unit of the first child is used
+func (g *ProgressGroup) SetUnit(u units.Unit) *ProgressGroup {
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.unit = u
+	return g
+}
+
+// SetClock sets the clock used by the group, the SystemClock is used by default
+func (g *ProgressGroup) SetClock(c Clock) *ProgressGroup {
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.clock = c
+	g.hub.clock = c
+	return g
+}
+
+// SetBlock sets blocking write to the Channel, see ProgressTracker.SetBlock
+func (g *ProgressGroup) SetBlock(b bool) *ProgressGroup {
+	g.m.Lock()
+	defer g.m.Unlock()
+	if b {
+		g.main.policy = Block
+	} else {
+		g.main.policy = DropNewest
+	}
+	return g
+}
+
+// SetData sets additional customers data to be sent with the combined progress updates
+func (g *ProgressGroup) SetData(d any) *ProgressGroup {
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.data = d
+	return g
+}
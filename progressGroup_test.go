@@ -0,0 +1,104 @@
+package progresso
+
+import (
+	"errors"
+	"github.com/archer-v/progresso/units/distance"
+	"testing"
+	"time"
+)
+
+// waitProgress reads the channel until the progress satisfying the condition is received
+func waitProgress(t *testing.T, ch <-chan Progress, cond func(p Progress) bool) Progress {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case p, ok := <-ch:
+			if !ok {
+				t.Fatal("channel is closed before the expected progress is received")
+			}
+			if cond(p) {
+				return p
+			}
+		case <-timeout:
+			t.Fatal("the expected progress isn't received")
+		}
+	}
+}
+
+func TestProgressGroup(t *testing.T) {
+	a := NewProgressTracker().SetUnit(distance.DistanceMetric).SetSize(1000).SetName("a")
+	b := NewProgressTracker().SetUnit(distance.DistanceMetric).SetName("b")
+	g := NewProgressGroup().SetName("group").Add(a, 0).Add(b, 0)
+	ch := g.Subscribe(10, DropNewest)
+
+	a.Increment(500)
+	p := waitProgress(t, ch, func(p Progress) bool { return p.Processed == 500 })
+	// b has unknown size and gets the same weight as a
+	if p.Percent != 25 {
+		t.Errorf("got percent %v, want 25", p.Percent)
+	}
+	if p.Total != -1 {
+		t.Errorf("got total %v, want -1", p.Total)
+	}
+	if len(p.Children) != 2 || p.Children[0].Name != "a" || p.Children[1].Name != "b" {
+		t.Errorf("wrong children breakdown: %+v", p.Children)
+	}
+
+	b.Increment(300)
+	b.Stop()
+	p = waitProgress(t, ch, func(p Progress) bool { return p.Processed == 800 && p.Percent == 75 })
+	if p.Finished {
+		t.Error("the group is finished before all children are finished")
+	}
+
+	a.Increment(500)
+	p = waitProgress(t, ch, func(p Progress) bool { return p.Finished })
+	if !p.Completed || p.Percent != 100 || p.Processed != 1300 {
+		t.Errorf("wrong final progress: %s", p.String())
+	}
+	if _, ok := <-ch; ok {
+		t.Error("the channel isn't closed after the group is finished")
+	}
+}
+
+func TestProgressGroupFailed(t *testing.T) {
+	a := NewProgressTracker().SetSize(100).SetName("a")
+	b := NewProgressTracker().SetSize(100).SetName("b")
+	c := NewProgressTracker().SetSize(100).SetName("c")
+	g := NewProgressGroup().Add(a, 0).Add(b, 0).Add(c, 0)
+	ch := g.Subscribe(10, DropNewest)
+
+	errFailed := errors.New("failed")
+	a.Cancel()
+	b.Fail(errFailed)
+	c.Increment(100)
+	p := waitProgress(t, ch, func(p Progress) bool { return p.Finished })
+	if p.Completed || !p.Failed || p.Cancelled {
+		t.Errorf("wrong final state: completed %v, failed %v, cancelled %v", p.Completed, p.Failed, p.Cancelled)
+	}
+	if p.Err != errFailed || p.Error != errFailed.Error() {
+		t.Errorf("got error %v, want %v", p.Err, errFailed)
+	}
+}
+
+func TestProgressGroupPaused(t *testing.T) {
+	start := time.Now().Add(-10 * time.Second)
+	g := NewProgressGroup()
+	g.children = []*groupChild{
+		{last: Progress{StartTime: start, Processed: 250, Total: 500, PausedTime: 5 * time.Second, Paused: true}},
+		{last: Progress{StartTime: start, Processed: 250, Total: 500, PausedTime: 6 * time.Second, Paused: true}},
+	}
+	p := g.Progress()
+	if !p.Paused || p.PausedTime != 5*time.Second {
+		t.Errorf("got paused %v for %v, want true for 5s", p.Paused, p.PausedTime)
+	}
+	// 50% are done in 5s of the active time
+	if p.Remaining < 4*time.Second || p.Remaining > 6*time.Second {
+		t.Errorf("got remaining %v, want 5s", p.Remaining)
+	}
+
+	g.children[1].last.Paused = false
+	if p = g.Progress(); p.Paused {
+		t.Error("the group is paused while a child is running")
+	}
+}
@@ -1,6 +1,7 @@
 package progresso
 
 import (
+	"context"
 	"io"
 	"os"
 )
@@ -8,7 +9,8 @@ import (
 // ProgressTrackerReader is a struct representing an io.ReaderCloser, which sends back progress
 // feedback over a channel
 type ProgressTrackerReader struct {
-	r io.ReadCloser
+	r       io.ReadCloser
+	limiter *RateLimiter
 	*ProgressTracker
 }
 
@@ -34,13 +36,27 @@ func NewProgressTrackerFileReader(file string) (*ProgressTrackerReader, <-chan P
 // NewProgressTrackerReader creates a new ProgressTrackerReader object based on the io.Reader and the
 // size you specified. Specify a size <= 0 if you don't know the size.
 func NewProgressTrackerReader(r io.Reader, size int64) (*ProgressTrackerReader, <-chan Progress) {
-	return newProgressTrackerReader(r, size, NewBytesProgressTracker().SetSize(size))
+	return returnReader(newProgressTrackerReader(r, size, NewBytesProgressTracker().SetSize(size)))
 }
 
-func newProgressTrackerReader(r io.Reader, size int64, tracker *ProgressTracker) (*ProgressTrackerReader, <-chan Progress) {
+// NewProgressTrackerReaderContext creates a new ProgressTrackerReader object like NewProgressTrackerReader
+// which is stopped when the context is done. Read returns the context error after that.
+func NewProgressTrackerReaderContext(ctx context.Context, r io.Reader, size int64) (*ProgressTrackerReader, <-chan Progress) {
+	return returnReader(newProgressTrackerReader(r, size, NewBytesProgressTracker().SetSize(size).SetContext(ctx)))
+}
+
+// returnReader returns the reader and its Channel to the caller of a constructor
+func returnReader(r *ProgressTrackerReader) (*ProgressTrackerReader, <-chan Progress) {
 	if r == nil {
 		return nil, nil
 	}
+	return r, r.returnChannel()
+}
+
+func newProgressTrackerReader(r io.Reader, size int64, tracker *ProgressTracker) *ProgressTrackerReader {
+	if r == nil {
+		return nil
+	}
 	rc, ok := r.(io.ReadCloser)
 	if !ok {
 		rc = io.NopCloser(r)
@@ -54,17 +70,42 @@ func newProgressTrackerReader(r io.Reader, size int64, tracker *ProgressTracker)
 		tracker.SetSize(size)
 	}
 
-	ret := &ProgressTrackerReader{rc, tracker}
-	return ret, ret.Channel
+	return &ProgressTrackerReader{r: rc, ProgressTracker: tracker}
 }
 
 // Read wraps the io.Reader Read function to also update the progress.
+// If the tracker context is done, the context error is returned.
+// A non-EOF error is reported in the final message marking it as Failed.
 func (p *ProgressTrackerReader) Read(b []byte) (n int, err error) {
+	if err = p.contextErr(); err != nil {
+		return 0, err
+	}
+	if p.limiter != nil {
+		if burst := p.limiter.Burst(); burst > 0 && int64(len(b)) > burst {
+			b = b[:burst]
+		}
+	}
 	n, err = p.r.Read(b)
 	p.Increment(int64(n))
+	if err != nil && err != io.EOF {
+		p.setErr(err)
+	}
+	if p.limiter != nil && n > 0 {
+		if werr := p.limiter.WaitN(p.getContext(), int64(n)); werr != nil && err == nil {
+			err = werr
+		}
+	}
 	return
 }
 
+// SetRateLimiter sets the limiter restricting the read rate. The limiter can be shared
+// by several readers and writers, its rate can be changed at runtime with RateLimiter.SetRate.
+// It should be set before the reading is started
+func (p *ProgressTrackerReader) SetRateLimiter(l *RateLimiter) *ProgressTrackerReader {
+	p.limiter = l
+	return p
+}
+
 // Close wraps the io.ReaderCloser Close function to clean up everything. ProgressTrackerReader
 // objects should always be closed to make sure everything is cleaned up.
 func (p *ProgressTrackerReader) Close() (err error) {
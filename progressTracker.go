@@ -1,13 +1,31 @@
 package progresso
 
 import (
+	"context"
+	"errors"
 	"github.com/archer-v/progresso/units"
 	"github.com/archer-v/progresso/units/bytes"
+	"github.com/archer-v/progresso/units/count"
 	"io"
 	"sync"
 	"time"
 )
 
+// ETASource defines which counter the remaining time of the tracker is calculated from
+type ETASource int
+
+const (
+	// ETAVolume calculates the remaining time from the work processed (default)
+	ETAVolume ETASource = iota
+	// ETAItems calculates the remaining time from the items processed
+	ETAItems
+	// ETABlend calculates the remaining time as an average of the volume and the items estimations
+	ETABlend
+)
+
+// ErrFailed is the error reported by the tracker failed with a nil error
+var ErrFailed = errors.New("progresso: operation failed")
+
 const (
 	// DefaultUpdateFreq defines frequency of the updates over the channels
 	DefaultUpdateFreq    = 100 * time.Millisecond
@@ -21,15 +39,45 @@ type ProgressTracker struct {
 	name                 string
 	size                 int64
 	progress             int64
-	block                bool
 	unit                 units.Unit
-	data                 any // additional data to be add to the progress updates
+	scale                int64 // the number of work values per unit of fractional work, see FloatProgressTracker
+	data                 any   // additional data to be add to the progress updates
+	onStart              []func(Progress)
+	onUpdate             []func(Progress)
+	onFinish             []func(Progress)
+	onStall              []func(Progress)
+	heartbeat            bool          // send updates periodically even without new work
+	stallTimeout         time.Duration // the time without new work the tracker is considered stalled after
+	stalled              bool
+	ticking              bool      // the ticker goroutine is started
+	lastWork             time.Time // when the work was recorded last time
 	Channel              chan Progress
+	channelReturned      bool            // the Channel is returned by a constructor, so it's never replaced
+	main                 *subscriber     // subscriber reading the Channel
+	hub                  hub             // all subscribers including the Channel one
+	ctx                  context.Context // context cancelling the tracker
+	ctxChanged           chan struct{}   // signals the context watcher that the context is replaced
+	clock                Clock
+	done                 chan struct{} // closed when the tracker is finished
 	closed               bool
+	cancelled            bool     // the tracker was cancelled
+	err                  error    // the error the tracker failed or was cancelled with
+	finished             bool     // the final message has been sent
+	final                Progress // the final message
 	startTime            time.Time
+	pausedAt             time.Time     // when the tracker was paused, zero if it isn't paused
+	pausedTime           time.Duration // accumulated paused time excluding the current pause
 	lastSent             time.Time
-	updatesW             []int64     // list of last work updates
-	updatesT             []time.Time // list of last time updates
+	estimator            Estimator
+	history              history
+	itemsEnabled         bool // the items counter is used
+	items                int64
+	itemsTotal           int64
+	itemsUnit            units.Unit
+	itemsEstimator       Estimator
+	lastItems            int64 // the items at the last recorded update
+	etaSource            ETASource
+	lastW                int64 // the work at the last recorded update
 	timeSlots            int
 	updateFreq           time.Duration
 	updateGranule        int64
@@ -42,15 +90,29 @@ type ProgressTracker struct {
 func NewProgressTracker() (p *ProgressTracker) {
 	p = &ProgressTracker{
 		Channel:       make(chan Progress),
+		done:          make(chan struct{}),
 		size:          -1,
+		itemsTotal:    -1,
+		itemsUnit:     count.Count,
 		updateFreq:    DefaultUpdateFreq,
 		updateGranule: DefaultUpdateGranule,
 		timeSlots:     DefaultTimeSlots,
+		clock:         SystemClock,
 	}
+	p.hub.clock = p.clock
+	p.main = p.hub.add(p.Channel, DropNewest)
+	p.estimator = NewSlidingWindowEstimator(p.timeSlots)
+	p.itemsEstimator = NewSlidingWindowEstimator(p.timeSlots)
 	p.Reset()
 	return
 }
 
+// NewProgressTrackerContext creates a new progress tracker stopped when the context is done.
+// The final message of the cancelled tracker is marked as Cancelled instead of Completed
+func NewProgressTrackerContext(ctx context.Context) *ProgressTracker {
+	return NewProgressTracker().SetContext(ctx)
+}
+
 // NewBytesProgressTracker creates a new progress tracker with bytes unit
 func NewBytesProgressTracker() *ProgressTracker {
 	return NewProgressTracker().SetUnit(bytes.BytesMetric)
@@ -80,57 +142,72 @@ func (p *ProgressTracker) Update(progress int64, data ...any) (prog Progress) {
 
 func (p *ProgressTracker) increment(progress int64, data ...any) (prog Progress) {
 
-	if p.closed && p.Channel == nil {
+	if p.finished {
 		// Nothing to do
 		return
 	}
 
+	curTime := p.clock.Now()
 	if progress > 0 {
 		p.progress += progress
-	}
-
-	if p.updatesW == nil {
-		p.updatesW = make([]int64, p.timeSlots)
-		p.updatesT = make([]time.Time, p.timeSlots)
+		p.lastWork = curTime
+		p.stalled = false
 	}
 
 	// Throttle sending updated, limit to updateFreq
 	// Always send when finished
-	if time.Since(p.lastSent) < p.updateFreq && !p.closed {
+	if curTime.Sub(p.lastSent) < p.updateFreq && !p.closed && !p.itemsDone() {
 		if (p.size <= 0) || (p.size > 0 && p.progress < p.size) {
 			return p.curProgress(data...)
 		}
 	}
 
-	curTime := time.Now()
-	if p.startTime.IsZero() {
+	started := p.startTime.IsZero()
+	if started {
 		p.startTime = curTime
+		p.lastWork = curTime
+		p.startTicker()
 	}
 
-	// saves update data to the current slot
-	p.updatesW[p.updatesCounter%p.timeSlots] = p.progress
-	p.updatesT[p.updatesCounter%p.timeSlots] = curTime
+	// saves update data to the estimator
+	p.estimator.Add(p.activeTime(curTime), p.progress)
+	if p.itemsEnabled {
+		p.itemsEstimator.Add(p.activeTime(curTime), p.items)
+	}
+	p.history.add(curTime, p.progress)
+	pp := p.lastW // previous progress
+	pItems := p.lastItems
+	p.lastW = p.progress
+	p.lastItems = p.items
 	p.updatesCounter++
 
 	prog = p.curProgress(data...)
+	if started {
+		runHooks(p.onStart, prog)
+	}
 
-	if p.closed || (p.size >= 0 && p.progress >= p.size) {
+	if p.closed || (p.size >= 0 && p.progress >= p.size) || p.itemsDone() {
 		// EOF or closed, we have to send this last message, and then close the chan
 		// Prevent sending the last message multiple times
-		prog.Completed = true
-		if p.Channel != nil {
-			prog.StopTime = curTime
-			prog.Finished = true
-			p.send(prog)
-			p.cleanup()
+		prog.Cancelled = p.cancelled
+		prog.Failed = p.err != nil && !p.cancelled
+		prog.Completed = !prog.Cancelled && !prog.Failed
+		if p.err != nil {
+			prog.Err = p.err
+			prog.Error = p.err.Error()
 		}
+		prog.StopTime = curTime
+		prog.Finished = true
+		p.final = prog
+		p.hub.sendFinal(prog)
+		runHooks(p.onUpdate, prog)
+		runHooks(p.onFinish, prog)
+		p.cleanup()
 		return
 	}
 
-	// filter updates except the first one
-	if p.updatesCounter > 1 {
-		pp := p.updatesW[(p.updatesCounter-2)%p.timeSlots]
-
+	// filter updates except the first one and ones with new items processed
+	if p.updatesCounter > 1 && p.items == pItems {
 		// do not send updates if the progress is the same as
 		// the previous one
 		if p.progress == pp {
@@ -140,7 +217,6 @@ func (p *ProgressTracker) increment(progress int64, data ...any) (prog Progress)
 		// skip updating the progress if the granule
 		// is the same as the previous one
 		if p.updateGranule > 1 {
-			// previous progress
 			if pp/p.updateGranule == p.progress/p.updateGranule {
 				return
 			}
@@ -161,12 +237,17 @@ func (p *ProgressTracker) increment(progress int64, data ...any) (prog Progress)
 }
 
 func (p *ProgressTracker) curProgress(data ...any) (progress Progress) {
+	curTime := p.clock.Now()
 	progress = Progress{
-		Name:      p.name,
-		Unit:      p.unit,
-		StartTime: p.startTime,
-		Processed: p.progress,
-		Total:     p.size,
+		Name:       p.name,
+		Unit:       p.unit,
+		Scale:      p.scale,
+		StartTime:  p.startTime,
+		Processed:  p.progress,
+		Total:      p.size,
+		Paused:     !p.pausedAt.IsZero(),
+		Stalled:    p.isStalled(curTime),
+		PausedTime: p.curPausedTime(curTime),
 	}
 
 	if data != nil && len(data) > 0 {
@@ -175,8 +256,8 @@ func (p *ProgressTracker) curProgress(data ...any) (progress Progress) {
 		progress.Data = p.data
 	}
 
-	// Calculate the average speed since starting
-	tp := time.Since(p.startTime)
+	// Calculate the average speed since starting excluding the paused time
+	tp := p.activeTime(curTime)
 	if tp > 0 {
 		progress.SpeedAvg = int64((float64(p.progress) / float64(tp)) * float64(time.Second))
 	} else {
@@ -184,26 +265,20 @@ func (p *ProgressTracker) curProgress(data ...any) (progress Progress) {
 	}
 
 	// Calculate the remaining time
-	if p.size > 0 && progress.SpeedAvg > 0 {
-		progress.Remaining = time.Duration((float64(p.size-p.progress) / float64(progress.SpeedAvg)) * float64(time.Second))
-		progress.RemainingS = int64(progress.Remaining / time.Second)
-		progress.EstStopTime = progress.StartTime.Add(progress.Remaining)
-	} else {
-		progress.Remaining = -1
-		progress.RemainingS = -1
-		progress.EstStopTime = time.Time{}
+	progress.Remaining = -1
+	progress.RemainingS = -1
+	if p.size > 0 {
+		if r := p.estimator.Remaining(tp, p.progress, p.size); r >= 0 {
+			progress.Remaining = r
+			progress.RemainingS = int64(progress.Remaining / time.Second)
+			progress.EstStopTime = progress.StartTime.Add(progress.Remaining)
+		}
 	}
 
-	if p.updatesT != nil &&
-		!p.updatesT[p.updatesCounter%p.timeSlots].IsZero() {
-		// Calculate the average speed of the last updateFreq * p.timeSlots seconds
-		progress.Speed = int64(
-			(float64(p.progress-p.updatesW[p.updatesCounter%p.timeSlots]) /
-				float64(time.Since(p.updatesT[p.updatesCounter%p.timeSlots]))) *
-				float64(time.Second))
-
+	if speed := p.estimator.Speed(tp, p.progress); speed >= 0 {
+		progress.Speed = int64(speed)
 	} else {
-		// do not calculate for first timeSlots updates
+		// do not calculate until the estimator has enough updates
 		progress.Speed = -1
 		progress.SpeedAvg = -1
 		progress.Remaining = -1
@@ -215,41 +290,264 @@ func (p *ProgressTracker) curProgress(data ...any) (progress Progress) {
 	if p.size > 0 {
 		progress.Percent = float64(int64((float64(p.progress)/float64(p.size))*10000.0)) / 100.0
 	}
+
+	if p.itemsEnabled {
+		progress.Items = p.curItemsProgress(tp)
+		if p.etaSource != ETAVolume {
+			r := progress.Items.Remaining
+			if p.etaSource == ETABlend && progress.Remaining >= 0 {
+				if r >= 0 {
+					r = (r + progress.Remaining) / 2
+				} else {
+					r = progress.Remaining
+				}
+			}
+			progress.Remaining = r
+			progress.RemainingS = -1
+			progress.EstStopTime = time.Time{}
+			if r >= 0 {
+				progress.RemainingS = int64(r / time.Second)
+				progress.EstStopTime = progress.StartTime.Add(r)
+			}
+		}
+	}
 	return
 }
 
+// curItemsProgress returns the progress of the items counter at the given active time
+func (p *ProgressTracker) curItemsProgress(tp time.Duration) *ItemsProgress {
+	ip := &ItemsProgress{
+		Processed: p.items,
+		Total:     p.itemsTotal,
+		Unit:      p.itemsUnit,
+		SpeedAvg:  -1,
+		Speed:     -1,
+		Remaining: -1,
+	}
+	if p.itemsTotal > 0 {
+		ip.Percent = float64(int64((float64(p.items)/float64(p.itemsTotal))*10000.0)) / 100.0
+	}
+	if speed := p.itemsEstimator.Speed(tp, p.items); speed >= 0 && tp > 0 {
+		ip.Speed = speed
+		ip.SpeedAvg = float64(p.items) / tp.Seconds()
+		if p.itemsTotal > 0 {
+			ip.Remaining = p.itemsEstimator.Remaining(tp, p.items, p.itemsTotal)
+		}
+	}
+	return ip
+}
+
+// itemsDone returns true if all items are processed while the size of the work is unknown
+func (p *ProgressTracker) itemsDone() bool {
+	return p.size < 0 && p.itemsEnabled && p.itemsTotal > 0 && p.items >= p.itemsTotal
+}
+
 func (p *ProgressTracker) cleanup() {
 	p.closed = true
-	if p.Channel != nil {
-		close(p.Channel)
-		p.Channel = nil
-	}
+	p.finished = true
+	close(p.done)
+	p.hub.close()
+	p.Channel = nil
 }
 
 func (p *ProgressTracker) send(prog Progress) {
-	if p.block {
-		p.Channel <- prog
-	} else {
-		// Don't force send, only send when it would not block, the chan is non-buffered
+	// Don't force send to non-blocking subscribers, a dropped update is retried
+	// on the next call since last sent values aren't updated
+	sent := p.hub.send(prog)
+	if len(p.onUpdate) > 0 {
+		runHooks(p.onUpdate, prog)
+		sent = true
+	}
+	if sent {
+		p.lastSent = p.clock.Now()
+	}
+}
+
+func runHooks(hooks []func(Progress), prog Progress) {
+	for _, h := range hooks {
+		h(prog)
+	}
+}
+
+// isStalled returns true if no work has been recorded for the stall timeout
+func (p *ProgressTracker) isStalled(t time.Time) bool {
+	return p.stallTimeout > 0 && !p.startTime.IsZero() && p.pausedAt.IsZero() &&
+		t.Sub(p.lastWork) >= p.stallTimeout
+}
+
+// startTicker starts the goroutine sending heartbeats and detecting stalls if they are enabled.
+// The goroutine lives until the tracker is finished with Stop, Cancel or Fail (or the work is completed)
+func (p *ProgressTracker) startTicker() {
+	if p.ticking || p.finished || p.startTime.IsZero() || (!p.heartbeat && p.stallTimeout <= 0) {
+		return
+	}
+	freq := p.updateFreq
+	if freq <= 0 {
+		freq = DefaultUpdateFreq
+	}
+	p.ticking = true
+	go p.runTicker(p.clock.NewTicker(freq))
+}
+
+func (p *ProgressTracker) runTicker(t Ticker) {
+	defer t.Stop()
+	for {
 		select {
-		case p.Channel <- prog:
-			// update last sent values
-			p.lastSent = time.Now()
-		default:
+		case <-t.C():
+			p.tick()
+		case <-p.done:
+			return
 		}
 	}
 }
 
+// tick sends the heartbeat update and fires the stall event
+func (p *ProgressTracker) tick() {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.finished || p.startTime.IsZero() || !p.pausedAt.IsZero() {
+		return
+	}
+	curTime := p.clock.Now()
+	stalled := p.isStalled(curTime)
+	stallEvent := stalled && !p.stalled
+	p.stalled = stalled
+	if !p.heartbeat && !stallEvent {
+		return
+	}
+
+	if p.heartbeat {
+		// record the sample without new work, so the speed decays
+		p.estimator.Add(p.activeTime(curTime), p.progress)
+		p.history.add(curTime, p.progress)
+		p.lastW = p.progress
+		p.updatesCounter++
+	}
+	prog := p.curProgress()
+	if stallEvent {
+		runHooks(p.onStall, prog)
+	}
+	if stallEvent || curTime.Sub(p.lastSent) >= p.updateFreq {
+		p.send(prog)
+	}
+}
+
+// IncrementItems increments the items counter at the given number of items processed
+// and fires the channel like Increment does
+func (p *ProgressTracker) IncrementItems(items int64, data ...any) Progress {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.itemsEnabled = true
+	if items > 0 && !p.finished {
+		p.items += items
+		p.lastWork = p.clock.Now()
+		p.stalled = false
+	}
+	return p.increment(0, data...)
+}
+
+// UpdateItems updates the items counter with the new number of items processed
+func (p *ProgressTracker) UpdateItems(items int64, data ...any) Progress {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.itemsEnabled = true
+	if items > p.items && !p.finished {
+		p.items = items
+		p.lastWork = p.clock.Now()
+		p.stalled = false
+		return p.increment(0, data...)
+	}
+	return p.curProgress(data...)
+}
+
+// Progress returns the current state of the progress tracker
+func (p *ProgressTracker) Progress() Progress {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.finished {
+		return p.final
+	}
+	return p.curProgress()
+}
+
+// Pause pauses the progress tracker. The time the tracker is paused is excluded
+// from the speed and remaining time calculation. The update marked as Paused is sent
+func (p *ProgressTracker) Pause() Progress {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.finished || !p.pausedAt.IsZero() {
+		return p.curProgress()
+	}
+	p.pausedAt = p.clock.Now()
+	prog := p.curProgress()
+	p.send(prog)
+	return prog
+}
+
+// Resume resumes the paused progress tracker and sends the update
+func (p *ProgressTracker) Resume() Progress {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.finished || p.pausedAt.IsZero() {
+		return p.curProgress()
+	}
+	curTime := p.clock.Now()
+	// the paused time isn't counted as the time without work
+	p.lastWork = p.lastWork.Add(curTime.Sub(p.pausedAt))
+	p.pausedTime = p.curPausedTime(curTime)
+	p.pausedAt = time.Time{}
+	prog := p.curProgress()
+	p.send(prog)
+	return prog
+}
+
+// curPausedTime returns the paused time accumulated to the given time
+func (p *ProgressTracker) curPausedTime(t time.Time) time.Duration {
+	if p.pausedAt.IsZero() || p.startTime.IsZero() {
+		return p.pausedTime
+	}
+	from := p.pausedAt
+	if from.Before(p.startTime) {
+		// the tracker was started while it's paused
+		from = p.startTime
+	}
+	return p.pausedTime + t.Sub(from)
+}
+
+// activeTime returns the time the tracker is active to the given time
+func (p *ProgressTracker) activeTime(t time.Time) time.Duration {
+	if p.startTime.IsZero() {
+		return 0
+	}
+	return t.Sub(p.startTime) - p.curPausedTime(t)
+}
+
+// Name returns the name of the progress tracker
+func (p *ProgressTracker) Name() string {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.name
+}
+
 // Reset resets the progress tracker to an initial state
 func (p *ProgressTracker) Reset() {
 	p.m.Lock()
 	defer p.m.Unlock()
 	p.progress = 0 // reset progress
 	p.startTime = time.Time{}
+	if !p.pausedAt.IsZero() {
+		p.pausedAt = p.clock.Now()
+	}
+	p.pausedTime = 0
 	p.lastSent = time.Time{}
-	p.updatesW = nil
-	p.updatesT = nil
+	p.estimator.Reset()
+	p.itemsEstimator.Reset()
+	p.items = 0
+	p.lastItems = 0
+	p.lastW = 0
 	p.updatesCounter = 0
+	p.stalled = false
+	p.history.reset()
 }
 
 // Stop stops the progress tracker, and sends the last message
@@ -260,16 +558,106 @@ func (p *ProgressTracker) Stop() Progress {
 	return p.increment(-1)
 }
 
+// Cancel stops the progress tracker, and sends the last message marked as Cancelled
+func (p *ProgressTracker) Cancel() Progress {
+	return p.cancel(context.Canceled)
+}
+
+// Fail stops the progress tracker, and sends the last message marked as Failed with the given error
+func (p *ProgressTracker) Fail(err error) Progress {
+	if err == nil {
+		err = ErrFailed
+	}
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.closed {
+		return Progress{}
+	}
+	p.closed = true
+	p.err = err
+	return p.increment(-1)
+}
+
+func (p *ProgressTracker) cancel(err error) Progress {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.cancelLocked(err)
+}
+
+func (p *ProgressTracker) cancelLocked(err error) Progress {
+	if p.closed {
+		return Progress{}
+	}
+	p.closed = true
+	p.cancelled = true
+	p.err = err
+	return p.increment(-1)
+}
+
+// setErr records the error to be reported in the final message
+func (p *ProgressTracker) setErr(err error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if !p.closed {
+		p.err = err
+	}
+}
+
+// watchContext cancels the tracker when its current context is done,
+// the replaced contexts are ignored
+func (p *ProgressTracker) watchContext(changed <-chan struct{}) {
+	for {
+		p.m.Lock()
+		ctx := p.ctx
+		p.m.Unlock()
+		var ctxDone <-chan struct{}
+		if ctx != nil {
+			ctxDone = ctx.Done()
+		}
+		select {
+		case <-ctxDone:
+			p.m.Lock()
+			if p.ctx == ctx {
+				p.cancelLocked(ctx.Err())
+				p.m.Unlock()
+				return
+			}
+			p.m.Unlock()
+		case <-changed:
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// getContext returns the tracker context or the background one if it isn't set
+func (p *ProgressTracker) getContext() context.Context {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.ctx == nil {
+		return context.Background()
+	}
+	return p.ctx
+}
+
+// contextErr returns the error of the tracker context if it's done
+func (p *ProgressTracker) contextErr() error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.ctx == nil {
+		return nil
+	}
+	return p.ctx.Err()
+}
+
 // GetWriter returns a ProgressTrackerWriter for the progress tracker
 func (p *ProgressTracker) GetWriter(w io.Writer, size int64) *ProgressTrackerWriter {
-	t, _ := newProgressTrackerWriter(w, size, p)
-	return t
+	return newProgressTrackerWriter(w, size, p)
 }
 
 // GetReader returns a ProgressTrackerReader for the progress tracker
 func (p *ProgressTracker) GetReader(r io.Reader, size int64) *ProgressTrackerReader {
-	t, _ := newProgressTrackerReader(r, size, p)
-	return t
+	return newProgressTrackerReader(r, size, p)
 }
 
 // SetSize sets the total size of the work to be done
@@ -280,6 +668,37 @@ func (p *ProgressTracker) SetSize(size int64) *ProgressTracker {
 	return p
 }
 
+// SetItems sets the total number of items to be processed and enables the items counter
+// tracked in addition to the work volume. Specify a total <= 0 if you don't know it.
+// If the size of the work is unknown, the tracker is completed when all items are processed
+func (p *ProgressTracker) SetItems(total int64) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.itemsEnabled = true
+	if total <= 0 {
+		total = -1
+	}
+	p.itemsTotal = total
+	return p
+}
+
+// SetItemsUnit sets the measurement unit of the items counter, count.Count by default
+func (p *ProgressTracker) SetItemsUnit(u units.Unit) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.itemsUnit = u
+	return p
+}
+
+// SetETASource sets the counter the remaining time is calculated from:
+// the work volume (default), the items or the blend of both
+func (p *ProgressTracker) SetETASource(src ETASource) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.etaSource = src
+	return p
+}
+
 // SetUpdateFreq sets the frequency at which to send updates
 func (p *ProgressTracker) SetUpdateFreq(freq time.Duration) *ProgressTracker {
 	p.m.Lock()
@@ -304,13 +723,23 @@ func (p *ProgressTracker) SetUpdateGranulePercent(percent int) *ProgressTracker
 	return p
 }
 
-// SetTimeSlots sets the number of time slots used to calculate an instant speed
+// SetTimeSlots sets the number of time slots used to calculate an instant speed,
+// it replaces the estimator with the SlidingWindowEstimator of the given size
 func (p *ProgressTracker) SetTimeSlots(slots int) *ProgressTracker {
 	p.m.Lock()
 	defer p.m.Unlock()
 	p.timeSlots = slots
-	p.updatesW = nil
-	p.updatesT = nil
+	p.estimator = NewSlidingWindowEstimator(slots)
+	p.itemsEstimator = NewSlidingWindowEstimator(slots)
+	return p
+}
+
+// SetEstimator sets the estimator used to calculate the speed and the remaining time
+func (p *ProgressTracker) SetEstimator(e Estimator) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	e.Reset()
+	p.estimator = e
 	return p
 }
 
@@ -333,11 +762,234 @@ func (p *ProgressTracker) SetUnit(u units.Unit) *ProgressTracker {
 // SetBlock sets blocking write to the Channel
 // to prevent possible messages lost if channel isn't reading state
 // use it carefully cause possible can lead to block Update / Increment
-// methods if the channel is full
+// methods if the channel is full.
+// SetBlock(true) sets the Block overflow policy replacing the one set by SetOverflowPolicy,
+// SetBlock(false) restores the DropNewest policy only if the policy is Block
 func (p *ProgressTracker) SetBlock(b bool) *ProgressTracker {
 	p.m.Lock()
 	defer p.m.Unlock()
-	p.block = b
+	if p.Channel == nil {
+		return p
+	}
+	if b {
+		p.main.policy = Block
+	} else if p.main.policy == Block {
+		p.main.policy = DropNewest
+	}
+	return p
+}
+
+// SetClock sets the clock used by the tracker, the SystemClock is used by default.
+// It should be set before the tracker is started
+func (p *ProgressTracker) SetClock(c Clock) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.clock = c
+	p.hub.clock = c
+	return p
+}
+
+// OnStart registers the hook called when the tracker records the first update.
+//
+// The hooks are called synchronously in the order of registration on the goroutine calling
+// the tracker method which has produced the update (Increment, Update, Stop, etc.),
+// on the internal goroutine watching the tracker context, or on the internal ticker goroutine
+// sending heartbeats and stall updates (see SetHeartbeat and SetStallTimeout). They are called while the tracker
+// is locked: a hook blocks the method until it returns, so it should be fast and it must not
+// call the tracker methods.
+func (p *ProgressTracker) OnStart(f func(Progress)) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.onStart = append(p.onStart, f)
+	return p
+}
+
+// OnUpdate registers the hook called on every update sent over the Channel,
+// it follows the same throttling rules and is called for the final update as well.
+// See OnStart on how the hooks are called
+func (p *ProgressTracker) OnUpdate(f func(Progress)) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.onUpdate = append(p.onUpdate, f)
+	return p
+}
+
+// OnFinish registers the hook called with the final update when the tracker is finished,
+// completed, cancelled or failed. See OnStart on how the hooks are called
+func (p *ProgressTracker) OnFinish(f func(Progress)) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.onFinish = append(p.onFinish, f)
+	return p
+}
+
+// SetHistory enables retaining the history of the updates with at most size samples
+// (minimum 3). When the history is full, its resolution is halved, so a long operation
+// is covered entirely. Zero size (default) disables the history
+func (p *ProgressTracker) SetHistory(size int) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if size > 0 && size < 3 {
+		size = 3
+	}
+	p.history.size = size
+	if size <= 0 {
+		p.history.reset()
+	}
+	for len(p.history.samples) > size && size > 0 {
+		p.history.downsample()
+	}
+	return p
+}
+
+// History returns the retained samples of the work processed, see SetHistory
+func (p *ProgressTracker) History() []Sample {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return append([]Sample(nil), p.history.samples...)
+}
+
+// Throughput returns the average speed over the consecutive intervals of the given length
+// calculated from the retained history, or the speed between every two samples if the length is <= 0
+func (p *ProgressTracker) Throughput(interval time.Duration) []Throughput {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.history.throughput(interval)
+}
+
+// SpeedStats returns the minimum, maximum and mean speed calculated from the retained history
+func (p *ProgressTracker) SpeedStats() SpeedStats {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.history.stats()
+}
+
+// OnStall registers the hook called when the tracker becomes stalled, see SetStallTimeout.
+// See OnStart on how the hooks are called
+func (p *ProgressTracker) OnStall(f func(Progress)) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.onStall = append(p.onStall, f)
+	return p
+}
+
+// SetHeartbeat enables sending the updates at the update frequency even if no new work is recorded,
+// so the consumer sees the speed decaying to zero when the work hangs.
+// The updates and the hooks are sent from an internal goroutine running until the tracker is finished,
+// so a tracker with the heartbeat enabled must be stopped, otherwise the goroutine leaks
+func (p *ProgressTracker) SetHeartbeat(enabled bool) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.heartbeat = enabled
+	p.startTicker()
+	return p
+}
+
+// SetStallTimeout sets the time without new work the tracker is considered stalled after,
+// the time the tracker is paused isn't counted.
+// The updates of the stalled tracker are marked as Stalled, the update and the OnStall hooks
+// are sent as soon as the tracker becomes stalled. Zero timeout (default) disables stall detection.
+// The stall is detected by an internal goroutine running until the tracker is finished,
+// like the one of SetHeartbeat, so the tracker must be stopped, otherwise the goroutine leaks
+func (p *ProgressTracker) SetStallTimeout(timeout time.Duration) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.stallTimeout = timeout
+	p.startTicker()
+	return p
+}
+
+// SetContext sets the context stopping the progress tracker when it's done.
+// The final message of the cancelled tracker is marked as Cancelled instead of Completed,
+// the Read/Write methods of the tracker readers and writers return the context error.
+// Only the last context set is watched. The context is watched by an internal goroutine
+// running until the tracker is finished or the context is done, so the tracker with
+// a context that is never done must be stopped, otherwise the goroutine leaks
+func (p *ProgressTracker) SetContext(ctx context.Context) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.ctx = ctx
+	if p.finished {
+		return p
+	}
+	if p.ctxChanged != nil {
+		select {
+		case p.ctxChanged <- struct{}{}:
+		default:
+		}
+	} else if ctx != nil {
+		p.ctxChanged = make(chan struct{}, 1)
+		go p.watchContext(p.ctxChanged)
+	}
+	return p
+}
+
+// SetOverflowPolicy sets the policy of sending updates to the Channel when the consumer isn't ready
+// to receive them. DropNewest (default) drops such updates including the final one, Block waits
+// for the consumer like SetBlock(true) does. Coalesce keeps the latest update in the channel buffer
+// replacing unread ones, so the consumer may miss intermediate updates but always receives the final one.
+// DropOldest evicts the oldest unread update from the channel buffer.
+// Coalesce and DropOldest replace an unbuffered Channel with a buffered one,
+// so they should be set before the Channel field is read. The Channel returned
+// by the reader and writer constructors is never replaced, so if it's unbuffered, the intermediate
+// updates the consumer isn't ready for are dropped, and the final update is delivered by a goroutine
+// waiting for the consumer. The goroutine leaks if the Channel is never read to the end
+func (p *ProgressTracker) SetOverflowPolicy(policy OverflowPolicy) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.Channel == nil {
+		return p
+	}
+	p.main.policy = policy
+	if (policy == Coalesce || policy == DropOldest) && cap(p.Channel) == 0 {
+		p.setBuffer(1)
+	}
+	return p
+}
+
+// SetBuffer replaces the Channel with the buffered one of the given size,
+// so it should be called before the Channel field is read.
+// The Channel returned by the reader and writer constructors is never replaced,
+// use Subscribe to get a buffered channel instead.
+// Use SetOverflowPolicy to define what happens with updates when the buffer is full
+func (p *ProgressTracker) SetBuffer(size int) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.Channel == nil || p.channelReturned || size < 0 {
+		return p
+	}
+	if size == 0 && (p.main.policy == Coalesce || p.main.policy == DropOldest) {
+		size = 1
+	}
+	p.setBuffer(size)
+	return p
+}
+
+func (p *ProgressTracker) setBuffer(size int) {
+	if p.channelReturned {
+		// the consumer may already read the channel
+		return
+	}
+	close(p.Channel)
+	p.Channel = make(chan Progress, size)
+	p.main.ch = p.Channel
+}
+
+// returnChannel returns the Channel to the caller of a constructor, it's never replaced after that
+func (p *ProgressTracker) returnChannel() <-chan Progress {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.channelReturned = true
+	return p.Channel
+}
+
+// SetBlockTimeout sets the maximum time to wait for a subscriber with the Block policy
+// (including the Channel if SetBlock is set) to receive an update, the update is dropped after that.
+// Zero timeout (default) means waiting forever
+func (p *ProgressTracker) SetBlockTimeout(timeout time.Duration) *ProgressTracker {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.hub.timeout = timeout
 	return p
 }
 
@@ -348,3 +1000,30 @@ func (p *ProgressTracker) SetData(d any) *ProgressTracker {
 	p.data = d
 	return p
 }
+
+// Subscribe creates a new channel receiving the progress updates independently of the Channel
+// and other subscribers. The buffer defines the channel capacity (at least 1), the policy defines
+// what happens with updates when the buffer is full. Regardless of the policy, every subscriber
+// receives the final message, and its channel is closed when the tracker is stopped
+func (p *ProgressTracker) Subscribe(buffer int, policy OverflowPolicy) <-chan Progress {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.finished {
+		// late subscriber gets the final message only
+		ch := make(chan Progress, 1)
+		ch <- p.final
+		close(ch)
+		return ch
+	}
+	return p.hub.subscribe(buffer, policy).ch
+}
+
+// Unsubscribe stops sending the updates to the channel returned by Subscribe and closes it
+// Don't call it while the tracker is blocked sending to the channel being unsubscribed
+func (p *ProgressTracker) Unsubscribe(ch <-chan Progress) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if s := p.hub.remove(ch); s != nil && s == p.main {
+		p.Channel = nil
+	}
+}
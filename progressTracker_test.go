@@ -2,6 +2,8 @@ package progresso
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"github.com/archer-v/progresso/units/distance"
 	"io"
 	"strings"
@@ -174,3 +176,304 @@ func TestProgressTrackerDistancePercentGranule(t *testing.T) {
 	<-done
 	t.Logf("done\n")
 }
+
+func TestProgressTrackerSubscribers(t *testing.T) {
+	r := NewProgressTracker().SetUnit(distance.DistanceMetric).SetSize(1000)
+	ch1 := r.Subscribe(1, DropNewest)
+	ch2 := r.Subscribe(10, DropNewest)
+	ch3 := r.Subscribe(1, DropNewest)
+	r.Unsubscribe(ch3)
+	if _, ok := <-ch3; ok {
+		t.Error("unsubscribed channel isn't closed")
+	}
+
+	// nobody reads the channels while the work is in progress
+	for i := 0; i < 50; i++ {
+		r.Increment(10)
+	}
+	r.Stop()
+
+	for i, ch := range []<-chan Progress{ch1, ch2} {
+		var last Progress
+		n := 0
+		for p := range ch {
+			last = p
+			n++
+		}
+		if !last.Finished {
+			t.Errorf("subscriber %d: the final message is lost", i+1)
+		}
+		if last.Processed != 500 {
+			t.Errorf("subscriber %d: got processed %d, want 500", i+1, last.Processed)
+		}
+		t.Logf("subscriber %d: %d messages received", i+1, n)
+	}
+
+	late := r.Subscribe(1, DropNewest)
+	if p, ok := <-late; !ok || !p.Finished {
+		t.Error("late subscriber doesn't receive the final message")
+	}
+}
+
+func TestProgressReaderContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, _ := NewProgressTrackerReaderContext(ctx, getReader(bufSize), bufSize)
+	ch := r.Subscribe(1, DropNewest)
+
+	buf := make([]byte, 1000)
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	cancel()
+
+	p := waitProgress(t, ch, func(p Progress) bool { return p.Finished })
+	if !p.Cancelled || p.Completed {
+		t.Errorf("the final progress isn't marked as cancelled: %+v", p)
+	}
+	if _, err := r.Read(buf); err != context.Canceled {
+		t.Errorf("got read error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestProgressTrackerReplaceContext(t *testing.T) {
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	r := NewProgressTracker().SetSize(100).SetContext(ctx1).SetContext(ctx2)
+	ch := r.Subscribe(1, DropNewest)
+
+	// the replaced context doesn't cancel the tracker
+	cancel1()
+	time.Sleep(10 * time.Millisecond)
+	if p := r.Progress(); p.Finished {
+		t.Fatalf("the tracker is cancelled by the replaced context: %+v", p)
+	}
+	cancel2()
+	p := waitProgress(t, ch, func(p Progress) bool { return p.Finished })
+	if !p.Cancelled {
+		t.Errorf("the final progress isn't marked as cancelled: %+v", p)
+	}
+}
+
+type failingWriter struct{}
+
+var errWrite = errors.New("write error")
+
+func (failingWriter) Write(b []byte) (int, error) {
+	return len(b) / 2, errWrite
+}
+
+func TestProgressTrackerFailAndCancel(t *testing.T) {
+	r := NewProgressTracker().SetSize(100)
+	ch := r.Subscribe(1, DropNewest)
+	r.Increment(10)
+	r.Cancel()
+	p := waitProgress(t, ch, func(p Progress) bool { return p.Finished })
+	if !p.Cancelled || p.Failed || p.Completed || p.Err != context.Canceled {
+		t.Errorf("wrong cancelled progress: %+v", p)
+	}
+
+	r = NewProgressTracker().SetSize(100)
+	ch = r.Subscribe(1, DropNewest)
+	r.Increment(10)
+	r.Fail(errWrite)
+	p = waitProgress(t, ch, func(p Progress) bool { return p.Finished })
+	if !p.Failed || p.Cancelled || p.Completed || p.Err != errWrite || p.Error != errWrite.Error() {
+		t.Errorf("wrong failed progress: %+v", p)
+	}
+
+	w, _ := NewProgressTrackerWriter(failingWriter{}, 100)
+	ch = w.Subscribe(1, DropNewest)
+	if _, err := w.Write(make([]byte, 10)); err != errWrite {
+		t.Fatalf("got write error %v, want %v", err, errWrite)
+	}
+	w.Close()
+	p = waitProgress(t, ch, func(p Progress) bool { return p.Finished })
+	if !p.Failed || p.Err != errWrite || p.Processed != 5 {
+		t.Errorf("wrong failed writer progress: %+v", p)
+	}
+}
+
+func TestProgressTrackerPause(t *testing.T) {
+	r := NewProgressTracker().SetSize(10000).SetUpdateFreq(0).SetTimeSlots(2)
+	ch := r.Subscribe(10, DropNewest)
+	r.Increment(0)
+	time.Sleep(100 * time.Millisecond)
+	r.Increment(100)
+	if p := r.Pause(); !p.Paused {
+		t.Error("the progress isn't marked as paused")
+	}
+	time.Sleep(300 * time.Millisecond)
+	p := r.Resume()
+	if p.Paused {
+		t.Error("the progress is marked as paused after resume")
+	}
+	if p.PausedTime < 300*time.Millisecond {
+		t.Errorf("got paused time %s, want at least 300ms", p.PausedTime)
+	}
+	// the speed is about 1000 units/sec if the paused time is excluded, and 250 units/sec otherwise
+	if p.SpeedAvg < 600 {
+		t.Errorf("got average speed %d, the paused time isn't excluded", p.SpeedAvg)
+	}
+	waitProgress(t, ch, func(p Progress) bool { return p.Paused })
+	waitProgress(t, ch, func(p Progress) bool { return !p.Paused })
+}
+
+func TestProgressTrackerHooks(t *testing.T) {
+	var started, updates, finished int
+	var last Progress
+	r := NewProgressTracker().SetSize(100).SetUpdateFreq(time.Hour).
+		OnStart(func(p Progress) { started++ }).
+		OnUpdate(func(p Progress) { updates++ }).
+		OnFinish(func(p Progress) { finished++; last = p })
+
+	for i := 0; i < 10; i++ {
+		r.Increment(10)
+	}
+	// the first update and the final one, others are throttled
+	if started != 1 || updates != 2 || finished != 1 {
+		t.Errorf("got %d start, %d update and %d finish hook calls, want 1, 2 and 1", started, updates, finished)
+	}
+	if !last.Finished || last.Processed != 100 {
+		t.Errorf("wrong final progress: %+v", last)
+	}
+}
+
+func TestProgressTrackerCoalesce(t *testing.T) {
+	// SetBlock(false) keeps the Coalesce policy
+	r := NewProgressTracker().SetSize(1000).SetUpdateFreq(0).SetOverflowPolicy(Coalesce).SetBlock(false)
+	ch := r.Channel
+	// nobody reads the channel while the work is in progress
+	for i := 0; i < 50; i++ {
+		r.Increment(10)
+	}
+	r.Stop()
+	var updates []Progress
+	for p := range ch {
+		updates = append(updates, p)
+	}
+	if len(updates) != 1 || !updates[0].Finished || updates[0].Processed != 500 {
+		t.Errorf("got updates %+v, want the final one only", updates)
+	}
+}
+
+func TestProgressTrackerBuffer(t *testing.T) {
+	r := NewProgressTracker().SetSize(1000).SetUpdateFreq(0).SetOverflowPolicy(DropOldest).SetBuffer(3)
+	ch := r.Channel
+	// nobody reads the channel while the work is in progress
+	for i := 0; i < 50; i++ {
+		r.Increment(10)
+	}
+	r.Stop()
+	var updates []Progress
+	for p := range ch {
+		updates = append(updates, p)
+	}
+	if len(updates) != 3 || updates[0].Processed != 490 || !updates[2].Finished {
+		t.Fatalf("got updates %+v, want the last three ones", updates)
+	}
+	if updates[2].Dropped != 48 {
+		t.Errorf("got %d dropped updates, want 48", updates[2].Dropped)
+	}
+}
+
+func TestProgressTrackerReaderBuffer(t *testing.T) {
+	r, ch := NewProgressTrackerReader(bytes.NewReader(make([]byte, 1000)), 1000)
+	// the returned channel is never replaced, so the consumer keeps receiving updates
+	r.SetOverflowPolicy(Coalesce).SetBuffer(3).SetUpdateFreq(0)
+	if (<-chan Progress)(r.Channel) != ch {
+		t.Fatal("the returned channel is replaced")
+	}
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	// the consumer starts reading after the tracker is finished, but still receives the final update
+	var last Progress
+	for p := range ch {
+		last = p
+	}
+	if !last.Finished || last.Processed != 1000 {
+		t.Errorf("wrong final progress: %+v", last)
+	}
+
+	w, wch := NewProgressTrackerWriter(io.Discard, 100)
+	w.SetBuffer(5)
+	if cap(wch) != 0 || (<-chan Progress)(w.Channel) != wch {
+		t.Error("the returned channel is replaced")
+	}
+	w.Close()
+}
+
+func TestProgressTrackerBlockTimeout(t *testing.T) {
+	r := NewProgressTracker().SetSize(1000).SetUpdateFreq(0).SetBlock(true).SetBlockTimeout(10 * time.Millisecond)
+	ch := r.Channel
+	r.Increment(10)
+	r.Increment(10)
+	done := make(chan Progress)
+	go func() {
+		done <- <-ch
+	}()
+	r.Increment(10)
+	if p := <-done; p.Processed != 30 || p.Dropped != 2 {
+		t.Errorf("got processed %d and %d dropped updates, want 30 and 2", p.Processed, p.Dropped)
+	}
+}
+
+func TestFloatProgressTracker(t *testing.T) {
+	r := NewFloatProgressTracker()
+	r.SetUnit(distance.DistanceMetric)
+	r.SetSize(10)
+	for i := 0; i < 25; i++ {
+		r.Increment(0.1)
+	}
+	p := r.Progress()
+	if p.Float(p.Processed) != 2.5 || p.Float(p.Total) != 10 || p.Percent != 25 {
+		t.Errorf("got processed %v of %v (%v%%), want 2.5 of 10 (25%%)", p.Float(p.Processed), p.Float(p.Total), p.Percent)
+	}
+	if s := p.String(); !strings.HasPrefix(s, "[25.00%] (2.50m/10m)") {
+		t.Errorf("wrong string representation: %s", s)
+	}
+	r.Update(10)
+	if p := r.Progress(); !p.Completed {
+		t.Errorf("the progress isn't completed: %+v", p)
+	}
+}
+
+func TestFloatProgressTrackerChain(t *testing.T) {
+	r := NewFloatProgressTracker().SetName("x").SetUnit(distance.DistanceMetric).SetUpdateFreq(0).SetSize(10)
+	r.Increment(2.5)
+	p := r.Progress()
+	if p.Name != "x" || p.Float(p.Total) != 10 || p.Percent != 25 {
+		t.Errorf("got %q processed %v of %v (%v%%), want x 2.5 of 10 (25%%)", p.Name, p.Float(p.Processed), p.Float(p.Total), p.Percent)
+	}
+	if s := p.String(); !strings.HasPrefix(s, "[25.00%] (2.50m/10m)") {
+		t.Errorf("wrong string representation: %s", s)
+	}
+}
+
+func TestFloatProgressTrackerReset(t *testing.T) {
+	r := NewFloatProgressTracker().SetScale(1).SetSize(10)
+	r.Increment(0.6)
+	r.Reset()
+	// the remainder of the previous run isn't carried over
+	if p := r.Increment(0.6); p.Processed != 0 {
+		t.Errorf("got processed %d after reset, want 0", p.Processed)
+	}
+}
+
+func TestProgressTrackerRegistryHooks(t *testing.T) {
+	reg := NewRegistry()
+	r := NewProgressTracker().SetName("a")
+	defer r.Stop()
+	for i := 0; i < 3; i++ {
+		if err := reg.Register(r); err != nil {
+			t.Fatal(err)
+		}
+		reg.Unregister("a")
+	}
+	// the hooks are installed once however many times the tracker is registered
+	if len(r.onUpdate) != 1 || len(r.onFinish) != 1 {
+		t.Errorf("got %d update and %d finish hooks, want 1", len(r.onUpdate), len(r.onFinish))
+	}
+}
@@ -1,6 +1,9 @@
 package progresso
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 // Copy functionality of io.NopCloser, but for Writers
 type nopWriteCloser struct{ io.Writer }
@@ -13,20 +16,35 @@ func getNopWriteCloser(w io.Writer) io.WriteCloser {
 // ProgressTrackerWriter is a struct representing an io.WriterCloser, which sends back progress
 // feedback over a channel
 type ProgressTrackerWriter struct {
-	w io.WriteCloser
+	w       io.WriteCloser
+	limiter *RateLimiter
 	*ProgressTracker
 }
 
 // NewProgressTrackerWriter creates a new ProgressTrackerWriter object based on the io.Writer and the
 // size you specified. Specify a size <= 0 if you don't know the size.
 func NewProgressTrackerWriter(w io.Writer, size int64) (*ProgressTrackerWriter, <-chan Progress) {
-	return newProgressTrackerWriter(w, size, NewBytesProgressTracker().SetSize(size))
+	return returnWriter(newProgressTrackerWriter(w, size, NewBytesProgressTracker().SetSize(size)))
 }
 
-func newProgressTrackerWriter(w io.Writer, size int64, tracker *ProgressTracker) (*ProgressTrackerWriter, <-chan Progress) {
+// NewProgressTrackerWriterContext creates a new ProgressTrackerWriter object like NewProgressTrackerWriter
+// which is stopped when the context is done. Write returns the context error after that.
+func NewProgressTrackerWriterContext(ctx context.Context, w io.Writer, size int64) (*ProgressTrackerWriter, <-chan Progress) {
+	return returnWriter(newProgressTrackerWriter(w, size, NewBytesProgressTracker().SetSize(size).SetContext(ctx)))
+}
+
+// returnWriter returns the writer and its Channel to the caller of a constructor
+func returnWriter(w *ProgressTrackerWriter) (*ProgressTrackerWriter, <-chan Progress) {
 	if w == nil {
 		return nil, nil
 	}
+	return w, w.returnChannel()
+}
+
+func newProgressTrackerWriter(w io.Writer, size int64, tracker *ProgressTracker) *ProgressTrackerWriter {
+	if w == nil {
+		return nil
+	}
 	wc, ok := w.(io.WriteCloser)
 	if !ok {
 		wc = getNopWriteCloser(w)
@@ -37,17 +55,65 @@ func newProgressTrackerWriter(w io.Writer, size int64, tracker *ProgressTracker)
 	if size >= 0 {
 		tracker.SetSize(size)
 	}
-	ret := &ProgressTrackerWriter{wc, tracker}
-	return ret, ret.Channel
+	return &ProgressTrackerWriter{w: wc, ProgressTracker: tracker}
 }
 
 // Write wraps the io.Writer Write function to also update the progress.
+// If the tracker context is done, the context error is returned.
+// A non-EOF error is reported in the final message marking it as Failed.
 func (p *ProgressTrackerWriter) Write(b []byte) (n int, err error) {
+	if err = p.contextErr(); err != nil {
+		return 0, err
+	}
+	if p.limiter != nil {
+		return p.writeLimited(b)
+	}
 	n, err = p.w.Write(b[0:])
 	p.Increment(int64(n))
+	if err != nil && err != io.EOF {
+		p.setErr(err)
+	}
 	return
 }
 
+// writeLimited writes the data in chunks of at most the limiter burst size
+// waiting for the limiter before every chunk
+func (p *ProgressTrackerWriter) writeLimited(b []byte) (n int, err error) {
+	ctx := p.getContext()
+	for len(b) > 0 {
+		chunk := b
+		if burst := p.limiter.Burst(); burst > 0 && int64(len(chunk)) > burst {
+			chunk = chunk[:burst]
+		}
+		if err = p.limiter.WaitN(ctx, int64(len(chunk))); err != nil {
+			return
+		}
+		var cn int
+		cn, err = p.w.Write(chunk)
+		n += cn
+		p.Increment(int64(cn))
+		if err == nil && cn == 0 {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			if err != io.EOF {
+				p.setErr(err)
+			}
+			return
+		}
+		b = b[cn:]
+	}
+	return
+}
+
+// SetRateLimiter sets the limiter restricting the write rate. The limiter can be shared
+// by several readers and writers, its rate can be changed at runtime with RateLimiter.SetRate.
+// It should be set before the writing is started
+func (p *ProgressTrackerWriter) SetRateLimiter(l *RateLimiter) *ProgressTrackerWriter {
+	p.limiter = l
+	return p
+}
+
 // Close wraps the io.WriterCloser Close function to clean up everything. ProgressTrackerWriter
 // objects should always be closed to make sure everything is cleaned up.
 func (p *ProgressTrackerWriter) Close() (err error) {
@@ -1,6 +1,7 @@
 package progresso
 
 import (
+	"github.com/archer-v/progresso/locale"
 	"github.com/archer-v/progresso/units/bytes"
 	"testing"
 	"time"
@@ -86,11 +87,77 @@ func TestPrintSize(t *testing.T) {
 	}
 }
 
+func TestPrintLocale(t *testing.T) {
+	p := Progress{
+		Unit:      bytes.BytesIEC,
+		Percent:   50.0,
+		Total:     bytes.MebiByte * 20,
+		Speed:     100 * bytes.KibiByte,
+		SpeedAvg:  100 * bytes.KibiByte, // 100KiB/sec,
+		Remaining: time.Second * 62,
+		Processed: bytes.MebiByte * 10,
+		StartTime: time.Now().Add(time.Second * -5),
+	}
+	for l, expect := range map[*locale.Locale]string{
+		locale.German:  "[50,00%] (10,00MiB/20,00MiB) (Geschwindigkeit: 100,00KiB/s / Ø: 100,00KiB/s) (Zeit: 5 Sekunden / Verbleibend: 1 Minute, 2 Sekunden)",
+		locale.Russian: "[50,00%] (10,00MiB/20,00MiB) (Скорость: 100,00KiB/s / Средняя: 100,00KiB/s) (Время: 5 секунд / Осталось: 1 минута, 2 секунды)",
+		locale.Polish:  "[50,00%] (10,00MiB/20,00MiB) (Prędkość: 100,00KiB/s / Średnia: 100,00KiB/s) (Czas: 5 sekund / Pozostało: 1 minuta, 2 sekundy)",
+	} {
+		p.StartTime = time.Now().Add(time.Second * -5)
+		if s := p.StringLocale(l); s != expect {
+			t.Errorf("TestPrintLocale %s:\n   Got     : '%s'\n   Expected: '%s'", l.Tag, s, expect)
+		}
+	}
+	// English is used if the locale is nil
+	if s, expect := p.StringLocale(nil), p.String(); s != expect {
+		t.Errorf("TestPrintLocale nil:\n   Got     : '%s'\n   Expected: '%s'", s, expect)
+	}
+}
+
+func TestFormatDurationLocale(t *testing.T) {
+	tests := []struct {
+		l      *locale.Locale
+		d      time.Duration
+		expect string
+	}{
+		{locale.English, 0, "0 seconds"},
+		{locale.English, -90 * time.Second, "1 minute, 30 seconds ago"},
+		{locale.German, 25 * time.Hour, "1 Tag, 1 Stunde"},
+		{locale.Russian, 22 * time.Minute, "22 минуты"},
+		{locale.Russian, 25 * time.Minute, "25 минут"},
+		{locale.Russian, -21 * time.Second, "21 секунда назад"},
+		{locale.Polish, 14 * 24 * time.Hour, "2 tygodnie"},
+		{locale.Polish, 5 * time.Hour, "5 godzin"},
+	}
+	for _, tt := range tests {
+		if s := FormatDurationLocale(tt.d, tt.l); s != tt.expect {
+			t.Errorf("FormatDurationLocale(%s, %s) = '%s', expected '%s'", tt.d, tt.l.Tag, s, tt.expect)
+		}
+	}
+	if s := FormatDurationLocale(90*time.Second, nil); s != "1 minute, 30 seconds" {
+		t.Errorf("FormatDurationLocale(1m30s, nil) = '%s', expected '1 minute, 30 seconds'", s)
+	}
+}
+
+func TestFormatClock(t *testing.T) {
+	for d, s := range map[time.Duration]string{
+		0:                            "00:00",
+		1500 * time.Millisecond:      "00:02",
+		75 * time.Second:             "01:15",
+		time.Hour + 2*time.Second:    "1:00:02",
+		-time.Second:                 "00:00",
+		26*time.Hour + 3*time.Minute: "26:03:00",
+	} {
+		if r := FormatClock(d); r != s {
+			t.Errorf("FormatClock(%s) = %q, expected %q", d, r, s)
+		}
+	}
+}
+
 func TestIOProgress(t *testing.T) {
 	iop := NewBytesProgressTracker().SetSize(100 * bytes.MebiByte)
 	iop.progress = 50 * bytes.MebiByte
-	iop.updatesW[1] = 40 * bytes.MebiByte
-	iop.updatesT[1] = time.Now().Add(time.Second * -1)
+	iop.estimator.Add(time.Second*9, 40*bytes.MebiByte)
 	iop.startTime = time.Now().Add(time.Second * -10)
 	go iop.Increment(0)
 	p := <-iop.Channel
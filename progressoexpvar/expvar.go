@@ -0,0 +1,43 @@
+// Package progressoexpvar publishes the progress of progresso trackers via expvar.
+// It's separated from progressohttp since importing expvar registers
+// the /debug/vars handler on http.DefaultServeMux
+package progressoexpvar
+
+import (
+	"expvar"
+
+	"github.com/archer-v/progresso/progressohttp"
+)
+
+// Publish publishes the progress of all trackers of the source under the given name.
+// The list is computed from the live state of the trackers on every scrape.
+// Like expvar.Publish it panics if the name is already registered
+func Publish(name string, src progressohttp.Source) {
+	expvar.Publish(name, Var(src))
+}
+
+// PublishTracker publishes the progress of the tracker or group under the given name.
+// The progress is computed from the live state of the tracker on every scrape.
+// Like expvar.Publish it panics if the name is already registered
+func PublishTracker(name string, t progressohttp.Tracker) {
+	expvar.Publish(name, TrackerVar(t))
+}
+
+// Var returns the expvar.Var of the progress of all trackers of the source
+func Var(src progressohttp.Source) expvar.Var {
+	return expvar.Func(func() any {
+		snapshots := src.Snapshots()
+		ret := make([]progressohttp.Status, 0, len(snapshots))
+		for _, p := range snapshots {
+			ret = append(ret, progressohttp.NewStatus(p))
+		}
+		return ret
+	})
+}
+
+// TrackerVar returns the expvar.Var of the progress of the tracker or group
+func TrackerVar(t progressohttp.Tracker) expvar.Var {
+	return expvar.Func(func() any {
+		return progressohttp.NewStatus(t.Progress())
+	})
+}
@@ -0,0 +1,38 @@
+package progressoexpvar
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/archer-v/progresso"
+	"github.com/archer-v/progresso/progressohttp"
+)
+
+func TestVar(t *testing.T) {
+	reg := progresso.NewRegistry()
+	tr := progresso.NewProgressTracker().SetName("t").SetSize(100).SetUpdateFreq(time.Hour)
+	defer tr.Stop()
+	if err := reg.Register(tr); err != nil {
+		t.Fatal(err)
+	}
+	// the vars are tested directly, since the published names can't be reused by repeated test runs
+	regVar, trVar := Var(reg), TrackerVar(tr)
+
+	tr.Increment(10)
+	tr.Increment(20) // throttled, isn't sent over the channel
+	var list []progressohttp.Status
+	if err := json.Unmarshal([]byte(regVar.String()), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Processed != 30 || list[0].Formatted.Percent != "30.00%" {
+		t.Errorf("wrong published list: %+v", list)
+	}
+	var s progressohttp.Status
+	if err := json.Unmarshal([]byte(trVar.String()), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "t" || s.Processed != 30 {
+		t.Errorf("wrong published tracker: %+v", s)
+	}
+}
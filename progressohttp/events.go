@@ -0,0 +1,118 @@
+package progressohttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/archer-v/progresso"
+)
+
+// Stream is a tracker the updates can be subscribed to,
+// progresso.ProgressTracker and progresso.ProgressGroup implement it
+type Stream interface {
+	Tracker
+	Subscribe(buffer int, policy progresso.OverflowPolicy) <-chan progresso.Progress
+	Unsubscribe(ch <-chan progresso.Progress)
+}
+
+// The names of the events sent by the events handlers
+const (
+	EventProgress = "progress" // an intermediate update
+	EventFinished = "finished" // the final update of the tracker
+)
+
+// NewEventsHandler creates a handler streaming the updates of the tracker as Server-Sent Events.
+// The current state is sent on connect, then every update sent by the tracker,
+// so the tracker update frequency and granule are respected.
+// The stream ends with the finished event
+func NewEventsHandler(s Stream) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := startEvents(w)
+		if flusher == nil {
+			return
+		}
+		// subscribe before taking the current state, so no update is missed in between
+		ch := s.Subscribe(1, progresso.Coalesce)
+		defer s.Unsubscribe(ch)
+		if p := s.Progress(); writeEvent(w, p) != nil || p.Finished {
+			return
+		}
+		flusher.Flush()
+		for {
+			select {
+			case p, ok := <-ch:
+				if !ok {
+					return
+				}
+				if writeEvent(w, p) != nil || p.Finished {
+					return
+				}
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+}
+
+// NewRegistryEventsHandler creates a handler streaming the updates of all trackers
+// of the registry as Server-Sent Events. The current state of every tracker is sent on connect,
+// the finished event is sent for every finished tracker, the stream lasts until the client disconnects
+func NewRegistryEventsHandler(reg *progresso.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := startEvents(w)
+		if flusher == nil {
+			return
+		}
+		ch := reg.Subscribe(16, progresso.DropOldest)
+		defer reg.Unsubscribe(ch)
+		for _, p := range reg.Snapshots() {
+			if writeEvent(w, p) != nil {
+				return
+			}
+		}
+		flusher.Flush()
+		for {
+			select {
+			case p := <-ch:
+				if writeEvent(w, p) != nil {
+					return
+				}
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+}
+
+// startEvents writes the headers of the event stream, it returns nil if streaming isn't supported
+func startEvents(w http.ResponseWriter) http.Flusher {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming isn't supported", http.StatusInternalServerError)
+		return nil
+	}
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return flusher
+}
+
+// writeEvent writes the progress as the Server-Sent Event
+func writeEvent(w http.ResponseWriter, p progresso.Progress) error {
+	b, err := json.Marshal(NewStatus(p))
+	if err != nil {
+		return err
+	}
+	event := EventProgress
+	if p.Finished {
+		event = EventFinished
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+	return err
+}
@@ -0,0 +1,108 @@
+package progressohttp
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/archer-v/progresso"
+)
+
+type event struct {
+	name   string
+	status Status
+}
+
+// readEvent reads the next Server-Sent Event from the stream
+func readEvent(t *testing.T, r *bufio.Reader) (e event) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("the event isn't received: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return
+		case strings.HasPrefix(line, "event: "):
+			e.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e.status); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestEventsHandler(t *testing.T) {
+	tr := progresso.NewProgressTracker().SetName("t").SetSize(100).SetUpdateFreq(0)
+	tr.Increment(10)
+	srv := httptest.NewServer(NewEventsHandler(tr))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("wrong content type %q", ct)
+	}
+	r := bufio.NewReader(resp.Body)
+	if e := readEvent(t, r); e.name != EventProgress || e.status.Processed != 10 {
+		t.Errorf("wrong initial event: %+v", e)
+	}
+	tr.Increment(20)
+	if e := readEvent(t, r); e.name != EventProgress || e.status.Processed != 30 {
+		t.Errorf("wrong update event: %+v", e)
+	}
+	tr.Increment(70)
+	if e := readEvent(t, r); e.name != EventFinished || !e.status.Completed || e.status.Processed != 100 {
+		t.Errorf("wrong finished event: %+v", e)
+	}
+	if _, err := r.ReadString('\n'); err == nil {
+		t.Error("the stream isn't closed after the finished event")
+	}
+}
+
+func TestRegistryEventsHandler(t *testing.T) {
+	reg := progresso.NewRegistry()
+	a := progresso.NewProgressTracker().SetName("a").SetUpdateFreq(0)
+	b := progresso.NewProgressTracker().SetName("b").SetUpdateFreq(0)
+	defer b.Stop()
+	for _, tr := range []*progresso.ProgressTracker{a, b} {
+		if err := reg.Register(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(NewRegistryEventsHandler(reg))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+	if e := readEvent(t, r); e.status.Name != "a" {
+		t.Errorf("wrong initial event: %+v", e)
+	}
+	if e := readEvent(t, r); e.status.Name != "b" {
+		t.Errorf("wrong initial event: %+v", e)
+	}
+	b.Increment(5)
+	if e := readEvent(t, r); e.name != EventProgress || e.status.Name != "b" || e.status.Processed != 5 {
+		t.Errorf("wrong update event: %+v", e)
+	}
+	a.Stop()
+	if e := readEvent(t, r); e.name != EventFinished || e.status.Name != "a" {
+		t.Errorf("wrong finished event: %+v", e)
+	}
+	b.Increment(5)
+	if e := readEvent(t, r); e.name != EventProgress || e.status.Name != "b" || e.status.Processed != 10 {
+		t.Errorf("wrong update event after another tracker is finished: %+v", e)
+	}
+}
@@ -0,0 +1,129 @@
+// Package progressohttp exposes the progress of progresso trackers over HTTP
+package progressohttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/archer-v/progresso"
+)
+
+// Source provides the progress snapshots served by the handlers,
+// progresso.Registry implements it
+type Source interface {
+	// Snapshots returns the current progress of all trackers
+	Snapshots() []progresso.Progress
+	// Snapshot returns the current progress of the tracker with the given name
+	Snapshot(name string) (progresso.Progress, bool)
+}
+
+// Tracker is anything reporting its current progress,
+// progresso.ProgressTracker and progresso.ProgressGroup implement it
+type Tracker interface {
+	Progress() progresso.Progress
+}
+
+// trackers is a fixed list of trackers used as a Source
+type trackers []Tracker
+
+// Trackers creates a Source from the fixed list of trackers, they are looked up by their names
+func Trackers(t ...Tracker) Source {
+	return trackers(t)
+}
+
+func (ts trackers) Snapshots() []progresso.Progress {
+	ret := make([]progresso.Progress, 0, len(ts))
+	for _, t := range ts {
+		ret = append(ret, t.Progress())
+	}
+	return ret
+}
+
+func (ts trackers) Snapshot(name string) (progresso.Progress, bool) {
+	for _, t := range ts {
+		if p := t.Progress(); p.Name == name {
+			return p, true
+		}
+	}
+	return progresso.Progress{}, false
+}
+
+// Status is the JSON representation of the progress served by the handler
+type Status struct {
+	progresso.Progress
+	Formatted Formatted `json:"formatted"`
+}
+
+// Formatted holds the human-readable values of the progress
+type Formatted struct {
+	Processed string `json:"processed"`           // The amount of work performed, "1.50MiB" for example
+	Total     string `json:"total,omitempty"`     // Total size of work, only available if the size is known
+	Percent   string `json:"percent,omitempty"`   // The progress in %, only available if the size is known
+	Speed     string `json:"speed,omitempty"`     // Work/sec of the last few works, "1.50MiB/s" for example
+	SpeedAvg  string `json:"speed_avg,omitempty"` // Work/sec average over the entire work
+	Remaining string `json:"remaining,omitempty"` // Estimated time remaining, only available if the size is known
+}
+
+// NewStatus creates the JSON representation of the progress
+func NewStatus(p progresso.Progress) Status {
+	f := Formatted{Processed: p.Format(p.Processed)}
+	// the speed is negative until it can be estimated
+	if p.Speed >= 0 {
+		f.Speed = p.Format(p.Speed) + "/s"
+	}
+	if p.SpeedAvg >= 0 {
+		f.SpeedAvg = p.Format(p.SpeedAvg) + "/s"
+	}
+	if p.Total > 0 {
+		f.Total = p.Format(p.Total)
+		f.Percent = fmt.Sprintf("%.2f%%", p.Percent)
+		if p.Remaining >= 0 && !p.Finished {
+			f.Remaining = progresso.FormatDuration(p.Remaining)
+		}
+	}
+	return Status{Progress: p, Formatted: f}
+}
+
+// NewHandler creates a handler serving the progress of the source as JSON.
+// The root path returns the list of all trackers, "/{name}" returns the single tracker,
+// use http.StripPrefix to mount the handler under a path:
+//
+//	http.Handle("/progress/", http.StripPrefix("/progress", progressohttp.NewHandler(progresso.DefaultRegistry)))
+func NewHandler(src Source) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		if name == "" {
+			snapshots := src.Snapshots()
+			ret := make([]Status, 0, len(snapshots))
+			for _, p := range snapshots {
+				ret = append(ret, NewStatus(p))
+			}
+			writeJSON(w, ret)
+			return
+		}
+		p, ok := src.Snapshot(name)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, NewStatus(p))
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write(append(b, '\n'))
+}
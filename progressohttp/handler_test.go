@@ -0,0 +1,94 @@
+package progressohttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/archer-v/progresso"
+	"github.com/archer-v/progresso/units/bytes"
+)
+
+func TestHandler(t *testing.T) {
+	reg := progresso.NewRegistry()
+	a := progresso.NewBytesProgressTracker().SetName("a").SetSize(4 * bytes.MegaByte)
+	b := progresso.NewProgressTracker().SetName("b")
+	defer a.Stop()
+	defer b.Stop()
+	for _, tr := range []*progresso.ProgressTracker{a, b} {
+		if err := reg.Register(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a.Increment(bytes.MegaByte)
+	b.Increment(5)
+
+	srv := httptest.NewServer(http.StripPrefix("/progress", NewHandler(reg)))
+	defer srv.Close()
+
+	var list []Status
+	if code := get(t, srv.URL+"/progress/", &list); code != http.StatusOK {
+		t.Fatalf("wrong status code %d", code)
+	}
+	if len(list) != 2 || list[0].Name != "a" || list[1].Name != "b" {
+		t.Fatalf("wrong list: %+v", list)
+	}
+	if f := list[0].Formatted; f.Processed != "1.00MB" || f.Total != "4.00MB" || f.Percent != "25.00%" {
+		t.Errorf("wrong formatted fields: %+v", f)
+	}
+	if list[0].Processed != bytes.MegaByte || list[0].Total != 4*bytes.MegaByte {
+		t.Errorf("wrong raw fields: %+v", list[0].Progress)
+	}
+	if f := list[1].Formatted; f.Processed != "5" || f.Total != "" || f.Remaining != "" {
+		t.Errorf("wrong formatted fields of unknown size: %+v", f)
+	}
+
+	var s Status
+	if code := get(t, srv.URL+"/progress/b", &s); code != http.StatusOK || s.Name != "b" || s.Processed != 5 {
+		t.Errorf("wrong single tracker response %d: %+v", code, s)
+	}
+	if code := get(t, srv.URL+"/progress/c", nil); code != http.StatusNotFound {
+		t.Errorf("wrong status code of unknown tracker %d", code)
+	}
+	resp, err := http.Post(srv.URL+"/progress/", "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("wrong status code of POST %d", resp.StatusCode)
+	}
+}
+
+func TestTrackers(t *testing.T) {
+	tr := progresso.NewProgressTracker().SetName("t")
+	defer tr.Stop()
+	src := Trackers(tr)
+	if p, ok := src.Snapshot("t"); !ok || p.Name != "t" {
+		t.Errorf("tracker isn't found: %+v", p)
+	}
+	if _, ok := src.Snapshot("x"); ok {
+		t.Error("unknown tracker is found")
+	}
+	if s := src.Snapshots(); len(s) != 1 {
+		t.Errorf("wrong snapshots: %+v", s)
+	}
+}
+
+func get(t *testing.T, url string, v any) int {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil && resp.StatusCode == http.StatusOK {
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("wrong content type %q", ct)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
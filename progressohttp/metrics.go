@@ -0,0 +1,88 @@
+package progressohttp
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/archer-v/progresso"
+)
+
+// metric is a gauge exported for every tracker
+type metric struct {
+	name  string
+	help  string
+	value func(p *progresso.Progress) (float64, bool) // returns false if the value isn't available
+}
+
+var metrics = []metric{
+	{"progresso_processed", "The amount of work performed.", func(p *progresso.Progress) (float64, bool) {
+		return p.Float(p.Processed), true
+	}},
+	{"progresso_total", "Total size of work.", func(p *progresso.Progress) (float64, bool) {
+		return p.Float(p.Total), p.Total > 0
+	}},
+	{"progresso_percent", "The progress of the work in percent.", func(p *progresso.Progress) (float64, bool) {
+		return p.Percent, p.Total > 0
+	}},
+	{"progresso_speed", "Work per second of the last few works.", func(p *progresso.Progress) (float64, bool) {
+		return p.Float(p.Speed), p.Speed >= 0
+	}},
+	{"progresso_speed_avg", "Work per second average over the entire work.", func(p *progresso.Progress) (float64, bool) {
+		return p.Float(p.SpeedAvg), p.SpeedAvg >= 0
+	}},
+	{"progresso_remaining_seconds", "Estimated time remaining in seconds.", func(p *progresso.Progress) (float64, bool) {
+		return p.Remaining.Seconds(), p.Total > 0 && p.Remaining >= 0 && !p.Finished
+	}},
+	{"progresso_finished", "1 if the work is finished.", func(p *progresso.Progress) (float64, bool) {
+		return boolValue(p.Finished), true
+	}},
+	{"progresso_completed", "1 if the work is completed.", func(p *progresso.Progress) (float64, bool) {
+		return boolValue(p.Completed), true
+	}},
+	{"progresso_cancelled", "1 if the work is cancelled.", func(p *progresso.Progress) (float64, bool) {
+		return boolValue(p.Cancelled), true
+	}},
+	{"progresso_failed", "1 if the work is failed.", func(p *progresso.Progress) (float64, bool) {
+		return boolValue(p.Failed), true
+	}},
+}
+
+// NewMetricsHandler creates a handler serving the progress of the source
+// in the Prometheus text exposition format. Every value is a gauge labeled
+// with the tracker name and the unit name, the values that aren't available
+// (the total of the work of unknown size for example) are omitted
+func NewMetricsHandler(src Source) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snapshots := src.Snapshots()
+		var buf bytes.Buffer
+		for _, m := range metrics {
+			fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name)
+			for i := range snapshots {
+				p := &snapshots[i]
+				if v, ok := m.value(p); ok {
+					fmt.Fprintf(&buf, "%s{name=\"%s\",unit=\"%s\"} %s\n",
+						m.name, escapeLabel(p.Name), escapeLabel(p.Unit.Name), strconv.FormatFloat(v, 'g', -1, 64))
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	})
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes the label value according to the exposition format
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
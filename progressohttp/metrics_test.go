@@ -0,0 +1,43 @@
+package progressohttp
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/archer-v/progresso"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	a := progresso.NewBytesProgressTracker().SetName("a").SetSize(200)
+	b := progresso.NewProgressTracker().SetName(`b "quoted"`)
+	defer a.Stop()
+	a.Increment(50)
+	b.Increment(5)
+	b.Stop()
+
+	rec := httptest.NewRecorder()
+	NewMetricsHandler(Trackers(a, b)).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("wrong content type %q", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	out := string(body)
+	for _, line := range []string{
+		"# TYPE progresso_processed gauge",
+		`progresso_processed{name="a",unit="BytesMetric"} 50`,
+		`progresso_total{name="a",unit="BytesMetric"} 200`,
+		`progresso_percent{name="a",unit="BytesMetric"} 25`,
+		`progresso_finished{name="a",unit="BytesMetric"} 0`,
+		`progresso_processed{name="b \"quoted\"",unit=""} 5`,
+		`progresso_completed{name="b \"quoted\"",unit=""} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("%q isn't found in:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, `progresso_total{name="b`) {
+		t.Errorf("total of unknown size is exported:\n%s", out)
+	}
+}
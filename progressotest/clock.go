@@ -0,0 +1,130 @@
+// Package progressotest provides utilities for testing code reporting the progress with progresso
+package progressotest
+
+import (
+	"github.com/archer-v/progresso"
+	"sync"
+	"time"
+)
+
+// Clock is a manual progresso.Clock, the time only moves when Set or Advance is called.
+// Timers and tickers created by the clock fire synchronously inside Set and Advance
+type Clock struct {
+	now     time.Time
+	timers  []*timer
+	tickers []*ticker
+	m       sync.Mutex
+}
+
+type timer struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+type ticker struct {
+	clock  *Clock
+	next   time.Time
+	period time.Duration
+	ch     chan time.Time
+}
+
+// NewClock creates a new manual clock set to the given time
+func NewClock(t time.Time) *Clock {
+	return &Clock{now: t}
+}
+
+// Now returns the current time of the clock
+func (c *Clock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.now
+}
+
+// Since returns the time elapsed since t
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Advance moves the clock forward by the given duration
+func (c *Clock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set sets the clock to the given time and fires the timers and tickers which are due.
+// A ticker fires once even if several periods have elapsed, like time.Ticker does with a slow receiver
+func (c *Clock) Set(t time.Time) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.now = t
+
+	timers := c.timers[:0]
+	for _, tm := range c.timers {
+		if tm.deadline.After(t) {
+			timers = append(timers, tm)
+			continue
+		}
+		tm.ch <- t
+	}
+	c.timers = timers
+
+	for _, tk := range c.tickers {
+		if tk.next.After(t) {
+			continue
+		}
+		select {
+		case tk.ch <- t:
+		default:
+		}
+		for !tk.next.After(t) {
+			tk.next = tk.next.Add(tk.period)
+		}
+	}
+}
+
+// After returns the channel receiving the clock time when the duration has elapsed
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.timers = append(c.timers, &timer{deadline: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// NewTicker returns a new ticker firing every period of the clock time
+func (c *Clock) NewTicker(d time.Duration) progresso.Ticker {
+	if d <= 0 {
+		panic("progressotest: non-positive interval for NewTicker")
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	tk := &ticker{
+		clock:  c,
+		next:   c.now.Add(d),
+		period: d,
+		ch:     make(chan time.Time, 1),
+	}
+	c.tickers = append(c.tickers, tk)
+	return tk
+}
+
+// C returns the channel on which the ticks are delivered
+func (t *ticker) C() <-chan time.Time {
+	return t.ch
+}
+
+// Stop turns off the ticker
+func (t *ticker) Stop() {
+	c := t.clock
+	c.m.Lock()
+	defer c.m.Unlock()
+	for i, tk := range c.tickers {
+		if tk == t {
+			c.tickers = append(c.tickers[:i], c.tickers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package progressotest
+
+import (
+	"github.com/archer-v/progresso"
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClock(start)
+	after := c.After(time.Second)
+	tk := c.NewTicker(300 * time.Millisecond)
+	defer tk.Stop()
+
+	c.Advance(500 * time.Millisecond)
+	select {
+	case <-after:
+		t.Error("the timer fired too early")
+	default:
+	}
+	select {
+	case tm := <-tk.C():
+		if !tm.Equal(start.Add(500 * time.Millisecond)) {
+			t.Errorf("got tick at %s", tm)
+		}
+	default:
+		t.Error("the ticker didn't fire")
+	}
+
+	c.Advance(500 * time.Millisecond)
+	select {
+	case <-after:
+	default:
+		t.Error("the timer didn't fire")
+	}
+	if got := c.Since(start); got != time.Second {
+		t.Errorf("got elapsed time %s, want 1s", got)
+	}
+}
+
+func TestTrackerWithClock(t *testing.T) {
+	c := NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := progresso.NewProgressTracker().
+		SetClock(c).
+		SetSize(1000).
+		SetTimeSlots(2).
+		SetUpdateFreq(time.Second)
+	ch := r.Subscribe(10, progresso.DropNewest)
+
+	r.Increment(100)
+	r.Increment(100) // throttled
+	c.Advance(time.Second)
+	r.Increment(100)
+
+	if p := <-ch; p.Processed != 100 {
+		t.Errorf("got processed %d in the first update, want 100", p.Processed)
+	}
+	p := <-ch
+	if p.Processed != 300 {
+		t.Errorf("got processed %d in the second update, want 300", p.Processed)
+	}
+	if p.Speed != 200 {
+		t.Errorf("got speed %d, want 200", p.Speed)
+	}
+	select {
+	case p = <-ch:
+		t.Errorf("unexpected update: %+v", p)
+	default:
+	}
+
+	c.Advance(time.Second)
+	p = r.Progress()
+	if p.Speed != 100 || p.SpeedAvg != 150 {
+		t.Errorf("got speed %d and average speed %d, want 100 and 150", p.Speed, p.SpeedAvg)
+	}
+	if p.Remaining != 70*time.Second/15 {
+		t.Errorf("got remaining %s, want %s", p.Remaining, 70*time.Second/15)
+	}
+}
@@ -0,0 +1,108 @@
+package progresso
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of work (bytes/sec for example) with the token bucket algorithm.
+// A single limiter can be shared by several tracker readers and writers,
+// so the whole group of transfers respects a single bandwidth budget
+type RateLimiter struct {
+	rate   float64 // units of work per second, <= 0 if the rate is unlimited
+	burst  int64   // the maximum amount of work allowed at once
+	tokens float64 // available amount of work, negative if it's reserved in advance
+	last   time.Time
+	clock  Clock
+	m      sync.Mutex
+}
+
+// NewRateLimiter creates a new rate limiter allowing rate units of work per second
+// with bursts of at most burst units. A burst <= 0 means the amount of work of one second
+func NewRateLimiter(rate, burst int64) *RateLimiter {
+	l := &RateLimiter{clock: SystemClock}
+	l.SetRate(rate, burst)
+	l.tokens = float64(l.burst)
+	return l
+}
+
+// SetRate changes the rate and the burst of the limiter at runtime,
+// a rate <= 0 disables the limit
+func (l *RateLimiter) SetRate(rate, burst int64) *RateLimiter {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.refill(l.clock.Now())
+	if burst <= 0 {
+		burst = rate
+	}
+	l.rate = float64(rate)
+	l.burst = burst
+	if l.tokens > float64(burst) {
+		l.tokens = float64(burst)
+	}
+	return l
+}
+
+// SetClock sets the clock used by the limiter, the SystemClock is used by default
+func (l *RateLimiter) SetClock(c Clock) *RateLimiter {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.clock = c
+	l.last = time.Time{}
+	return l
+}
+
+// Rate returns the rate of the limiter in units of work per second
+func (l *RateLimiter) Rate() int64 {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return int64(l.rate)
+}
+
+// Burst returns the maximum amount of work allowed at once
+func (l *RateLimiter) Burst() int64 {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.burst
+}
+
+// refill adds the tokens accumulated since the last call
+func (l *RateLimiter) refill(t time.Time) {
+	if !l.last.IsZero() && l.rate > 0 {
+		l.tokens += t.Sub(l.last).Seconds() * l.rate
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+	}
+	l.last = t
+}
+
+// WaitN blocks until n units of work are allowed or the context is done.
+// The work is reserved in advance, so the concurrent callers are served in order
+func (l *RateLimiter) WaitN(ctx context.Context, n int64) error {
+	l.m.Lock()
+	if l.rate <= 0 || n <= 0 {
+		l.m.Unlock()
+		return nil
+	}
+	clock := l.clock
+	l.refill(clock.Now())
+	l.tokens -= float64(n)
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.m.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-clock.After(wait):
+		return nil
+	case <-ctx.Done():
+		// give the reserved work back
+		l.m.Lock()
+		l.tokens += float64(n)
+		l.m.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,122 @@
+package progresso_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/archer-v/progresso"
+	"github.com/archer-v/progresso/progressotest"
+	"io"
+	"testing"
+	"time"
+)
+
+// waitClock is the test clock reporting the waits of the limiter
+type waitClock struct {
+	*progressotest.Clock
+	waits chan time.Duration
+}
+
+func newWaitClock() waitClock {
+	return waitClock{
+		Clock: progressotest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		waits: make(chan time.Duration, 10),
+	}
+}
+
+func (c waitClock) After(d time.Duration) <-chan time.Time {
+	ch := c.Clock.After(d)
+	c.waits <- d
+	return ch
+}
+
+func waitDone(t *testing.T, done <-chan error) {
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the wait isn't finished")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	c := newWaitClock()
+	l := progresso.NewRateLimiter(100000, 10000).SetClock(c) // 100 kB/s with 10 kB bursts
+	ctx := context.Background()
+
+	// the burst is allowed at once
+	if err := l.WaitN(ctx, 10000); err != nil || len(c.waits) != 0 {
+		t.Fatalf("the burst isn't allowed at once, error %v", err)
+	}
+	// two callers share the same bandwidth budget
+	first, second := make(chan error, 1), make(chan error, 1)
+	go func() { first <- l.WaitN(ctx, 10000) }()
+	if d := <-c.waits; d != 100*time.Millisecond {
+		t.Errorf("got the first wait %s, want 100ms", d)
+	}
+	go func() { second <- l.WaitN(ctx, 10000) }()
+	if d := <-c.waits; d != 200*time.Millisecond {
+		t.Errorf("got the second wait %s, want 200ms", d)
+	}
+	c.Advance(100 * time.Millisecond)
+	waitDone(t, first)
+	select {
+	case <-second:
+		t.Error("the second wait is finished too early")
+	default:
+	}
+	c.Advance(100 * time.Millisecond)
+	waitDone(t, second)
+
+	// the writer waits for the limiter before every chunk of the burst size
+	c.Advance(100 * time.Millisecond) // refill the burst
+	w, _ := progresso.NewProgressTrackerWriter(io.Discard, 25000)
+	w.SetRateLimiter(l)
+	written := make(chan error, 1)
+	go func() {
+		n, err := w.Write(make([]byte, 25000))
+		if n != 25000 {
+			t.Errorf("got %d bytes written", n)
+		}
+		written <- err
+	}()
+	for _, want := range []time.Duration{100 * time.Millisecond, 50 * time.Millisecond} {
+		if d := <-c.waits; d != want {
+			t.Errorf("got the writer wait %s, want %s", d, want)
+		}
+		c.Advance(want)
+	}
+	waitDone(t, written)
+
+	l.SetRate(0, 0)
+	r, _ := progresso.NewProgressTrackerReader(bytes.NewReader(make([]byte, 1000000)), 1000000)
+	r.SetRateLimiter(l)
+	if n, _ := io.Copy(io.Discard, r); n != 1000000 || len(c.waits) != 0 {
+		t.Errorf("got %d bytes read and %d waits for unlimited rate", n, len(c.waits))
+	}
+}
+
+func TestRateLimiterContext(t *testing.T) {
+	c := newWaitClock()
+	l := progresso.NewRateLimiter(1000, 1000).SetClock(c)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := l.WaitN(ctx, 1000); err != nil {
+		t.Fatalf("unexpected error for the burst: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- l.WaitN(ctx, 1000) }()
+	<-c.waits
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	// the reserved work is given back on cancel
+	go func() { done <- l.WaitN(context.Background(), 1000) }()
+	if d := <-c.waits; d != time.Second {
+		t.Errorf("got wait %s after cancel, want 1s", d)
+	}
+	c.Advance(time.Second)
+	waitDone(t, done)
+}
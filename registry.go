@@ -0,0 +1,243 @@
+package progresso
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoName is returned on registering a tracker without a name
+	ErrNoName = errors.New("progresso: tracker has no name")
+	// ErrDuplicateName is returned on registering a tracker with a name of another active tracker
+	ErrDuplicateName = errors.New("progresso: tracker name is already registered")
+)
+
+// DefaultRegistry is the default registry of trackers
+var DefaultRegistry = NewRegistry()
+
+// Registry is a collection of trackers registered by name. It lists the active trackers,
+// fetches their snapshots and broadcasts all their updates on one merged stream.
+// A tracker is removed from the registry when it's finished, its final progress can be
+// retained as archived for the retention period.
+//
+// The registry receives the updates with the tracker hooks, so a subscriber with the Block
+// policy blocks the trackers as well
+type Registry struct {
+	trackers   map[string]*ProgressTracker
+	registered map[*ProgressTracker]*registration // the registrations of the active trackers
+	hooked     map[*ProgressTracker]*registration // the registrations the hooks of the unfinished trackers are installed for
+	archived   map[string]archivedProgress
+	retention  time.Duration
+	clock      Clock
+	hub        hub
+	m          sync.Mutex
+}
+
+// registration is the tracker registered by name. The hooks of the tracker are installed once
+// and reuse the registration if the tracker is registered again, they're ignored while it's unregistered
+type registration struct {
+	name string // the name the tracker is registered by, it doesn't change if the tracker is renamed
+}
+
+// archivedProgress is the final progress of the finished tracker
+type archivedProgress struct {
+	progress Progress
+	expires  time.Time
+}
+
+// NewRegistry creates a new empty registry
+func NewRegistry() (r *Registry) {
+	r = &Registry{
+		trackers:   make(map[string]*ProgressTracker),
+		registered: make(map[*ProgressTracker]*registration),
+		hooked:     make(map[*ProgressTracker]*registration),
+		archived:   make(map[string]archivedProgress),
+		clock:      SystemClock,
+	}
+	r.hub.clock = r.clock
+	return
+}
+
+// Register adds the tracker to the registry by its name. The tracker stays registered
+// by this name if it's renamed later, register it again to use the new name
+func (r *Registry) Register(t *ProgressTracker) error {
+	name := t.Name()
+	if name == "" {
+		return ErrNoName
+	}
+	r.m.Lock()
+	if cur, ok := r.trackers[name]; ok && cur != t {
+		r.m.Unlock()
+		return ErrDuplicateName
+	}
+	if reg, ok := r.registered[t]; ok && reg.name != name {
+		// the tracker is renamed, it's registered by the new name
+		delete(r.trackers, reg.name)
+	}
+	reg, hooked := r.hooked[t]
+	if !hooked {
+		reg = &registration{}
+		r.hooked[t] = reg
+	}
+	reg.name = name
+	r.registered[t] = reg
+	r.trackers[name] = t
+	delete(r.archived, name)
+	r.m.Unlock()
+
+	// the tracker methods are never called while the registry is locked,
+	// since the tracker hooks lock the registry
+	if !hooked {
+		t.OnUpdate(func(p Progress) { r.update(t, reg, p) })
+		t.OnFinish(func(p Progress) { r.finish(t, reg, p) })
+	}
+	if p := t.Progress(); p.Finished {
+		r.finish(t, reg, p)
+	}
+	return nil
+}
+
+// Unregister removes the tracker with the given name from the registry.
+// The hooks of the tracker are kept and reused if it's registered again
+func (r *Registry) Unregister(name string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if t, ok := r.trackers[name]; ok {
+		delete(r.registered, t)
+		delete(r.trackers, name)
+	}
+	delete(r.archived, name)
+}
+
+// update broadcasts the update of the registered tracker
+func (r *Registry) update(t *ProgressTracker, reg *registration, p Progress) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.registered[t] != reg {
+		return
+	}
+	if p.Finished {
+		r.hub.sendFinal(p)
+		return
+	}
+	r.hub.send(p)
+}
+
+// finish archives the final progress of the registered tracker
+func (r *Registry) finish(t *ProgressTracker, reg *registration, p Progress) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.hooked[t] == reg {
+		// the tracker is finished, its hooks aren't called anymore
+		delete(r.hooked, t)
+	}
+	if r.registered[t] != reg {
+		return
+	}
+	delete(r.trackers, reg.name)
+	delete(r.registered, t)
+	if r.retention > 0 {
+		r.archived[reg.name] = archivedProgress{progress: p, expires: r.clock.Now().Add(r.retention)}
+	}
+}
+
+// purge removes the expired archived progresses
+func (r *Registry) purge() {
+	now := r.clock.Now()
+	for name, a := range r.archived {
+		if !now.Before(a.expires) {
+			delete(r.archived, name)
+		}
+	}
+}
+
+// Get returns the active tracker with the given name
+func (r *Registry) Get(name string) (*ProgressTracker, bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	t, ok := r.trackers[name]
+	return t, ok
+}
+
+// List returns the active trackers sorted by name
+func (r *Registry) List() []*ProgressTracker {
+	r.m.Lock()
+	ret := make([]*ProgressTracker, 0, len(r.trackers))
+	for _, t := range r.trackers {
+		ret = append(ret, t)
+	}
+	r.m.Unlock()
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name() < ret[j].Name() })
+	return ret
+}
+
+// Snapshot returns the current progress of the active or archived tracker with the given name
+func (r *Registry) Snapshot(name string) (Progress, bool) {
+	r.m.Lock()
+	r.purge()
+	t, ok := r.trackers[name]
+	a, archived := r.archived[name]
+	r.m.Unlock()
+	if ok {
+		return t.Progress(), true
+	}
+	return a.progress, archived
+}
+
+// Snapshots returns the current progress of the active trackers
+// followed by the archived ones, both sorted by name
+func (r *Registry) Snapshots() []Progress {
+	ret := make([]Progress, 0)
+	for _, t := range r.List() {
+		ret = append(ret, t.Progress())
+	}
+	return append(ret, r.Archived()...)
+}
+
+// Archived returns the final progress of the archived trackers sorted by name
+func (r *Registry) Archived() []Progress {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.purge()
+	ret := make([]Progress, 0, len(r.archived))
+	for _, a := range r.archived {
+		ret = append(ret, a.progress)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// Subscribe creates a new channel receiving the updates of all registered trackers,
+// see ProgressTracker.Subscribe. The channel is closed on Unsubscribe only
+func (r *Registry) Subscribe(buffer int, policy OverflowPolicy) <-chan Progress {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.hub.subscribe(buffer, policy).ch
+}
+
+// Unsubscribe stops sending the updates to the channel returned by Subscribe and closes it
+func (r *Registry) Unsubscribe(ch <-chan Progress) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.hub.remove(ch)
+}
+
+// SetRetention sets the period the final progress of the finished tracker is retained as archived,
+// zero retention (default) removes the tracker from the registry as soon as it's finished
+func (r *Registry) SetRetention(d time.Duration) *Registry {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.retention = d
+	return r
+}
+
+// SetClock sets the clock used by the registry, the SystemClock is used by default
+func (r *Registry) SetClock(c Clock) *Registry {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.clock = c
+	r.hub.clock = c
+	return r
+}
@@ -0,0 +1,122 @@
+package progresso_test
+
+import (
+	"github.com/archer-v/progresso"
+	"github.com/archer-v/progresso/progressotest"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	c := progressotest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	reg := progresso.NewRegistry().SetClock(c).SetRetention(time.Minute)
+	ch := reg.Subscribe(10, progresso.DropNewest)
+	defer reg.Unsubscribe(ch)
+
+	a := progresso.NewProgressTracker().SetName("a").SetSize(100).SetClock(c)
+	b := progresso.NewProgressTracker().SetName("b").SetSize(100).SetClock(c)
+	if err := reg.Register(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register(progresso.NewProgressTracker().SetName("a")); err != progresso.ErrDuplicateName {
+		t.Errorf("duplicate name is registered: %v", err)
+	}
+	if err := reg.Register(progresso.NewProgressTracker()); err != progresso.ErrNoName {
+		t.Errorf("tracker without name is registered: %v", err)
+	}
+	if l := reg.List(); len(l) != 2 || l[0] != a || l[1] != b {
+		t.Errorf("wrong list of trackers: %v", l)
+	}
+
+	a.Increment(10)
+	if p := receive(t, ch); p.Name != "a" || p.Processed != 10 {
+		t.Errorf("wrong merged update: %+v", p)
+	}
+	b.Increment(20)
+	if p := receive(t, ch); p.Name != "b" || p.Processed != 20 {
+		t.Errorf("wrong merged update: %+v", p)
+	}
+	if s := reg.Snapshots(); len(s) != 2 || s[0].Processed != 10 || s[1].Processed != 20 {
+		t.Errorf("wrong snapshots: %+v", s)
+	}
+
+	a.Stop()
+	if p := receive(t, ch); p.Name != "a" || !p.Finished {
+		t.Errorf("wrong final update: %+v", p)
+	}
+	if _, ok := reg.Get("a"); ok {
+		t.Error("finished tracker is active")
+	}
+	if p, ok := reg.Snapshot("a"); !ok || !p.Finished || p.Processed != 10 {
+		t.Errorf("finished tracker isn't archived: %+v", p)
+	}
+	if s := reg.Snapshots(); len(s) != 2 || s[0].Name != "b" || s[1].Name != "a" {
+		t.Errorf("wrong snapshots: %+v", s)
+	}
+
+	c.Advance(time.Minute)
+	if _, ok := reg.Snapshot("a"); ok {
+		t.Error("archived tracker isn't removed after the retention period")
+	}
+
+	reg.Unregister("b")
+	b.Increment(10)
+	select {
+	case p := <-ch:
+		t.Errorf("update of unregistered tracker is received: %+v", p)
+	default:
+	}
+	if l := reg.List(); len(l) != 0 {
+		t.Errorf("wrong list of trackers: %v", l)
+	}
+	b.Stop()
+}
+
+func TestRegistryRename(t *testing.T) {
+	reg := progresso.NewRegistry()
+	ch := reg.Subscribe(10, progresso.DropNewest)
+	defer reg.Unsubscribe(ch)
+
+	a := progresso.NewProgressTracker().SetName("a").SetUpdateFreq(0)
+	if err := reg.Register(a); err != nil {
+		t.Fatal(err)
+	}
+	a.SetName("b")
+	a.Increment(10)
+	if p := receive(t, ch); p.Name != "b" || p.Processed != 10 {
+		t.Errorf("update of renamed tracker isn't received: %+v", p)
+	}
+	if tr, ok := reg.Get("a"); !ok || tr != a {
+		t.Error("renamed tracker isn't found by the registered name")
+	}
+
+	// registering again moves the tracker to the new name without duplicating the updates
+	reg.Unregister("a")
+	if err := reg.Register(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register(a); err != nil {
+		t.Fatal(err)
+	}
+	a.Increment(10)
+	receive(t, ch)
+	select {
+	case p := <-ch:
+		t.Errorf("duplicated update is received: %+v", p)
+	default:
+	}
+
+	a.Stop()
+	if p := receive(t, ch); !p.Finished {
+		t.Errorf("wrong final update: %+v", p)
+	}
+	if _, ok := reg.Get("b"); ok {
+		t.Error("finished renamed tracker is active")
+	}
+	if l := reg.List(); len(l) != 0 {
+		t.Errorf("wrong list of trackers: %v", l)
+	}
+}
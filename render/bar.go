@@ -0,0 +1,356 @@
+// Package render draws the progress of progresso trackers on a terminal
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/archer-v/progresso"
+)
+
+// Tracker is a source of the progress updates,
+// progresso.ProgressTracker and progresso.ProgressGroup implement it
+type Tracker interface {
+	Progress() progresso.Progress
+	Subscribe(buffer int, policy progresso.OverflowPolicy) <-chan progresso.Progress
+	Unsubscribe(ch <-chan progresso.Progress)
+}
+
+// ANSI escape sequences
+const (
+	colorReset = "\x1b[0m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+	clearLine  = "\x1b[K"
+)
+
+// the partial blocks filling 1/8 to 8/8 of a cell
+var blocks = []rune(" ▏▎▍▌▋▊▉█")
+
+// the frames of the spinner
+var (
+	spinnerASCII   = []rune(`|/-\`)
+	spinnerUnicode = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+)
+
+// Bar draws the progress as a single line: name, percent, bar, processed/total, speed and ETA.
+// When the size of the work is unknown, the indeterminate bar bouncing or the spinner spinning
+// on every frame is drawn instead, the determinate bar is drawn as soon as the size is known.
+// When the output isn't a terminal, a plain line without the bar is written on every update
+type Bar struct {
+	w         io.Writer
+	width     int // 0 if the terminal width is detected on every draw
+	tty       bool
+	color     bool
+	unicode   bool
+	spinner   bool // draw the spinner instead of the bouncing bar if the size is unknown
+	frame     int  // the number of the animation frame
+	frameRate time.Duration
+	clock     progresso.Clock
+	m         sync.Mutex
+}
+
+// NewBar creates a new bar drawing to the writer. The terminal mode is enabled if the writer is a terminal,
+// the color is enabled on terminals unless NO_COLOR is set to a non-empty value, the Unicode blocks are used if the locale is UTF-8
+func NewBar(w io.Writer) *Bar {
+	tty := IsTerminal(w)
+	return &Bar{
+		w:         w,
+		tty:       tty,
+		color:     tty && colorEnabled(),
+		unicode:   unicodeEnabled(),
+		frameRate: DefaultFrameRate,
+		clock:     progresso.SystemClock,
+	}
+}
+
+// SetWidth sets the width of the line, 0 (default) means the terminal width
+func (b *Bar) SetWidth(width int) *Bar {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.width = width
+	return b
+}
+
+// SetTTY enables the terminal mode redrawing the line in place,
+// otherwise a plain line is written on every update
+func (b *Bar) SetTTY(tty bool) *Bar {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.tty = tty
+	return b
+}
+
+// SetColor enables the colored output
+func (b *Bar) SetColor(color bool) *Bar {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.color = color
+	return b
+}
+
+// SetUnicode enables the Unicode partial blocks, otherwise the bar is drawn with ASCII characters
+func (b *Bar) SetUnicode(unicode bool) *Bar {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.unicode = unicode
+	return b
+}
+
+// SetSpinner enables the spinner instead of the bouncing bar when the size of the work is unknown
+func (b *Bar) SetSpinner(spinner bool) *Bar {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.spinner = spinner
+	return b
+}
+
+// SetFrameRate sets the interval between redraws of the animation while tracking,
+// DefaultFrameRate is used by default
+func (b *Bar) SetFrameRate(d time.Duration) *Bar {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.frameRate = d
+	return b
+}
+
+// SetClock sets the clock driving the animation, the SystemClock is used by default
+func (b *Bar) SetClock(c progresso.Clock) *Bar {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.clock = c
+	return b
+}
+
+// Line returns the line representing the progress fitted into the width
+func (b *Bar) Line(p progresso.Progress, width int) string {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.line(p, width, true)
+}
+
+// Draw draws the progress and advances the animation. In the terminal mode the line is redrawn in place
+// and the new line is started when the progress is finished
+func (b *Bar) Draw(p progresso.Progress) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.frame++
+	if !b.tty {
+		_, err := io.WriteString(b.w, b.line(p, 0, false)+"\n")
+		return err
+	}
+	s := "\r" + b.line(p, b.getWidth(), true) + clearLine
+	if p.Finished {
+		s += "\n"
+	}
+	_, err := io.WriteString(b.w, s)
+	return err
+}
+
+// Run draws every update received from the channel until the final one or until the channel is closed
+func (b *Bar) Run(ch <-chan progresso.Progress) error {
+	for p := range ch {
+		if err := b.Draw(p); err != nil {
+			return err
+		}
+		if p.Finished {
+			break
+		}
+	}
+	return nil
+}
+
+// Track draws the current progress of the tracker and its updates until it's finished.
+// In the terminal mode the current progress is also redrawn at the frame rate,
+// so the animation is running while no update is sent
+func (b *Bar) Track(t Tracker) error {
+	ch := t.Subscribe(1, progresso.Coalesce)
+	defer t.Unsubscribe(ch)
+	if p := t.Progress(); !p.Finished {
+		if err := b.Draw(p); err != nil {
+			return err
+		}
+	}
+	b.m.Lock()
+	tty, frameRate, clock := b.tty, b.frameRate, b.clock
+	b.m.Unlock()
+	if !tty {
+		return b.Run(ch)
+	}
+	if frameRate <= 0 {
+		frameRate = DefaultFrameRate
+	}
+	ticker := clock.NewTicker(frameRate)
+	defer ticker.Stop()
+	for {
+		select {
+		case p, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := b.Draw(p); err != nil || p.Finished {
+				return err
+			}
+		case <-ticker.C():
+			// the final progress is drawn when it's received from the channel
+			if p := t.Progress(); !p.Finished {
+				if err := b.Draw(p); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// advance advances the animation
+func (b *Bar) advance() {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.frame++
+}
+
+func (b *Bar) getWidth() int {
+	if b.width > 0 {
+		return b.width
+	}
+	return TerminalWidth(b.w)
+}
+
+// line builds the line fitted into the width, the bar is drawn if it's enabled and fits
+func (b *Bar) line(p progresso.Progress, width int, bar bool) string {
+	prefix := ""
+	if p.Name != "" {
+		prefix = p.Name + " "
+	}
+	if p.Total > 0 {
+		prefix += fmt.Sprintf("%6.2f%% ", p.Percent)
+	}
+	info := info(p)
+	if !bar || (p.Total <= 0 && p.Finished) {
+		return fit(prefix+info, width)
+	}
+	// the last column is left empty, so the line is never wrapped
+	barWidth := width - 1 - utf8.RuneCountInString(prefix+info) - 3
+	if p.Total <= 0 {
+		if b.spinner || barWidth < 5 {
+			return fit(b.spin()+" "+prefix+info, width)
+		}
+		return prefix + b.bounce(p, barWidth) + " " + info
+	}
+	if barWidth < 5 {
+		return fit(prefix+info, width)
+	}
+	return prefix + b.bar(p, barWidth) + " " + info
+}
+
+// spin returns the current frame of the spinner
+func (b *Bar) spin() string {
+	frames := spinnerASCII
+	if b.unicode {
+		frames = spinnerUnicode
+	}
+	return string(frames[b.frame%len(frames)])
+}
+
+// bounce draws the indeterminate bar of the given width including the borders,
+// the segment moves from one border to another by one cell on every frame
+func (b *Bar) bounce(p progresso.Progress, width int) string {
+	seg := width / 5
+	if seg < 3 {
+		seg = 3
+	}
+	span := width - seg
+	pos := b.frame % (2 * span)
+	if pos > span {
+		pos = 2*span - pos
+	}
+	fill, left, right := strings.Repeat("=", seg), "[", "]"
+	if b.unicode {
+		fill, left, right = strings.Repeat(string(blocks[8]), seg), "│", "│"
+	}
+	if b.color {
+		fill = stateColor(p) + fill + colorReset
+	}
+	return left + strings.Repeat(" ", pos) + fill + strings.Repeat(" ", span-pos) + right
+}
+
+// bar draws the bar of the given width including the borders
+func (b *Bar) bar(p progresso.Progress, width int) string {
+	fill := p.Percent / 100
+	if fill < 0 {
+		fill = 0
+	} else if fill > 1 {
+		fill = 1
+	}
+	var cells, left, right string
+	if b.unicode {
+		eighths := int(fill * float64(width*8))
+		cells = strings.Repeat(string(blocks[8]), eighths/8)
+		if eighths%8 > 0 {
+			cells += string(blocks[eighths%8])
+		}
+		left, right = "│", "│"
+	} else {
+		n := int(fill * float64(width))
+		cells = strings.Repeat("=", n)
+		if n < width && n > 0 {
+			cells = cells[:n-1] + ">"
+		}
+		left, right = "[", "]"
+	}
+	cells += strings.Repeat(" ", width-utf8.RuneCountInString(cells))
+	if b.color {
+		cells = stateColor(p) + cells + colorReset
+	}
+	return left + cells + right
+}
+
+// stateColor returns the color of the bar representing the progress state
+func stateColor(p progresso.Progress) string {
+	switch {
+	case p.Cancelled || p.Failed:
+		return colorRed
+	case p.Completed:
+		return colorGreen
+	}
+	return colorCyan
+}
+
+// info returns the processed/total, speed and ETA or the final state of the progress
+func info(p progresso.Progress) string {
+	s := p.Format(p.Processed)
+	if p.Total > 0 {
+		s += "/" + p.Format(p.Total)
+	}
+	if p.Speed >= 0 && !p.Finished {
+		s += " " + p.Format(p.Speed) + "/s"
+	}
+	switch {
+	case p.Failed:
+		s += " failed: " + p.Error
+	case p.Cancelled:
+		s += " cancelled"
+	case p.Finished:
+		s += " done in " + progresso.FormatClock(p.StopTime.Sub(p.StartTime)-p.PausedTime)
+	case p.Paused:
+		s += " paused"
+	case p.Stalled:
+		s += " stalled"
+	case p.Total > 0 && p.Remaining >= 0:
+		s += " ETA " + progresso.FormatClock(p.Remaining)
+	}
+	return s
+}
+
+// fit truncates the string to the width leaving the last column empty, 0 width means no limit
+func fit(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) < width {
+		return s
+	}
+	return string([]rune(s)[:width-1])
+}
@@ -0,0 +1,193 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/archer-v/progresso"
+	"github.com/archer-v/progresso/progressotest"
+	"github.com/archer-v/progresso/units/bytes"
+)
+
+func testProgress() progresso.Progress {
+	return progresso.Progress{
+		Name:      "file",
+		Processed: 2500,
+		Total:     10000,
+		Percent:   25,
+		Speed:     1000,
+		Unit:      bytes.BytesMetric,
+		Remaining: 75 * time.Second,
+	}
+}
+
+func TestBarLine(t *testing.T) {
+	b := NewBar(&strings.Builder{}).SetColor(false).SetUnicode(false)
+	line := b.Line(testProgress(), 60)
+	if line != "file  25.00% [=>        ] 2.50kB/10.00kB 1.00kB/s ETA 01:15" {
+		t.Errorf("wrong ASCII line %q", line)
+	}
+	if n := utf8.RuneCountInString(line); n != 59 {
+		t.Errorf("wrong line width %d", n)
+	}
+
+	b.SetUnicode(true)
+	p := testProgress()
+	p.Percent = 12.5
+	if line := b.Line(p, 60); !strings.Contains(line, "│█▎") || utf8.RuneCountInString(line) != 59 {
+		t.Errorf("wrong Unicode line %q", line)
+	}
+
+	b.SetColor(true)
+	if line := b.Line(p, 60); !strings.Contains(line, colorCyan) || !strings.Contains(line, colorReset) {
+		t.Errorf("line isn't colored %q", line)
+	}
+
+	if line := b.Line(p, 30); strings.Contains(line, "│") || utf8.RuneCountInString(line) != 29 {
+		t.Errorf("bar isn't omitted in narrow line %q", line)
+	}
+
+	p.Total = 0
+	if line := b.SetColor(false).Line(p, 60); line != "file │███████                             │ 2.50kB 1.00kB/s" {
+		t.Errorf("wrong line of unknown size %q", line)
+	}
+
+	p = testProgress()
+	p.Finished, p.Completed, p.Percent, p.Processed = true, true, 100, 10000
+	p.StopTime = p.StartTime.Add(10 * time.Second)
+	if line := b.SetColor(false).Line(p, 60); !strings.HasSuffix(line, "10.00kB/10.00kB done in 00:10") {
+		t.Errorf("wrong finished line %q", line)
+	}
+}
+
+func TestBarIndeterminate(t *testing.T) {
+	var out strings.Builder
+	b := NewBar(&out).SetTTY(true).SetWidth(30).SetColor(false).SetUnicode(false)
+	p := progresso.Progress{Name: "t", Processed: 5, Speed: -1, Remaining: -1}
+	// 30 - 1 - 3 - 3 = 23 cells, 4 cells segment
+	for _, bar := range []string{
+		"[ ====                  ]",
+		"[  ====                 ]",
+	} {
+		b.Draw(p)
+		if s := out.String(); s != "\rt "+bar+" 5"+clearLine {
+			t.Errorf("wrong bouncing bar %q", s)
+		}
+		out.Reset()
+	}
+	b.frame = 21
+	if line := b.Line(p, 30); line != "t [                 ====  ] 5" {
+		t.Errorf("bar doesn't bounce off the border %q", line)
+	}
+
+	b.SetSpinner(true)
+	b.Draw(p)
+	if s := out.String(); s != "\r- t 5"+clearLine {
+		t.Errorf("wrong spinner %q", s)
+	}
+	out.Reset()
+
+	// switches to the determinate bar as soon as the size is known
+	p.Total, p.Percent = 10, 50
+	if line := b.Line(p, 30); line != "t  50.00% [=====>      ] 5/10" {
+		t.Errorf("wrong determinate line %q", line)
+	}
+}
+
+func TestBarTrackFrames(t *testing.T) {
+	c := progressotest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	var out safeBuilder
+	b := NewBar(&out).SetTTY(true).SetWidth(40).SetColor(false).SetUnicode(false).SetClock(c).SetFrameRate(time.Second)
+	tr := progresso.NewProgressTracker().SetName("t").SetUpdateFreq(time.Hour)
+	done := make(chan error)
+	go func() {
+		done <- b.Track(tr)
+	}()
+	waitOutput(t, &out, "\rt [")
+	out.Reset()
+
+	// no update is sent, the animation is redrawn on the frame tick
+	c.Advance(time.Second)
+	waitOutput(t, &out, "\rt [")
+	out.Reset()
+
+	tr.SetSize(10)
+	c.Advance(time.Second)
+	waitOutput(t, &out, "\rt   0.00% [")
+	tr.Stop()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitOutput waits until the output starts with the prefix
+func waitOutput(t *testing.T, out *safeBuilder, prefix string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.HasPrefix(out.String(), prefix) {
+		if time.Now().After(deadline) {
+			t.Fatalf("output %q doesn't start with %q", out.String(), prefix)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBarDraw(t *testing.T) {
+	var out strings.Builder
+	b := NewBar(&out).SetTTY(true).SetWidth(60).SetColor(false)
+	p := testProgress()
+	if err := b.Draw(p); err != nil {
+		t.Fatal(err)
+	}
+	if s := out.String(); !strings.HasPrefix(s, "\r") || !strings.HasSuffix(s, clearLine) {
+		t.Errorf("line isn't redrawn in place %q", s)
+	}
+	out.Reset()
+	p.Finished = true
+	b.Draw(p)
+	if s := out.String(); !strings.HasSuffix(s, clearLine+"\n") {
+		t.Errorf("new line isn't started when finished %q", s)
+	}
+
+	out.Reset()
+	b.SetTTY(false)
+	b.Draw(testProgress())
+	if s := out.String(); s != "file  25.00% 2.50kB/10.00kB 1.00kB/s ETA 01:15\n" {
+		t.Errorf("wrong plain line %q", s)
+	}
+}
+
+func TestBarTrack(t *testing.T) {
+	var out strings.Builder
+	tr := progresso.NewProgressTracker().SetName("t").SetSize(100).SetUpdateFreq(0)
+	done := make(chan error)
+	go func() {
+		done <- NewBar(&out).Track(tr)
+	}()
+	for i := 0; i < 10; i++ {
+		tr.Increment(10)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("tracking isn't finished")
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, "t 100.00% 100/100 done in") {
+		t.Errorf("wrong last line %q", last)
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	for value, want := range map[string]bool{"": true, "1": false} {
+		t.Setenv("NO_COLOR", value)
+		if got := colorEnabled(); got != want {
+			t.Errorf("colorEnabled() with NO_COLOR=%q = %v, want %v", value, got, want)
+		}
+	}
+}
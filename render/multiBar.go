@@ -0,0 +1,291 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/archer-v/progresso"
+)
+
+// DefaultFrameRate is the default interval between redraws of the MultiBar
+const DefaultFrameRate = 100 * time.Millisecond
+
+// ANSI escape sequences moving the cursor
+const (
+	cursorUp    = "\x1b[%dA"
+	clearScreen = "\x1b[J" // clears from the cursor to the end of the screen
+)
+
+// MultiBar draws the bars of many concurrent trackers redrawing them in place at a single frame rate.
+// The finished bars are pinned above the active ones or removed, an optional total bar is drawn below them.
+// The log lines written with Write, Printf or Println are printed above the bars.
+// When the output isn't a terminal, only the final line of every tracker and the log lines are written
+type MultiBar struct {
+	w         io.Writer
+	bar       *Bar
+	trackers  []*progresso.ProgressTracker
+	total     *progresso.ProgressGroup
+	totalDone bool // the final line of the total bar is written in the plain mode
+	remove    bool // remove the finished bars instead of pinning them
+	frameRate time.Duration
+	clock     progresso.Clock
+	lines     int    // the number of lines of the last frame
+	frame     string // the active bars of the last frame, they're redrawn below the log lines
+	stop      chan struct{}
+	done      chan struct{}
+	m         sync.Mutex
+	dm        sync.Mutex // serializes the redraws
+}
+
+// NewMultiBar creates a new multi-bar drawing to the writer,
+// the terminal settings are detected like NewBar does
+func NewMultiBar(w io.Writer) *MultiBar {
+	return &MultiBar{
+		w:         w,
+		bar:       NewBar(w),
+		frameRate: DefaultFrameRate,
+		clock:     progresso.SystemClock,
+	}
+}
+
+// Bar returns the bar used to draw the lines, its settings are applied to all lines
+func (mb *MultiBar) Bar() *Bar {
+	return mb.bar
+}
+
+// Add adds the tracker to draw
+func (mb *MultiBar) Add(t *progresso.ProgressTracker) *MultiBar {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+	mb.trackers = append(mb.trackers, t)
+	if mb.total != nil {
+		mb.total.Add(t, 0)
+	}
+	return mb
+}
+
+// SetTotal enables the total bar with the given name combining all trackers,
+// the trackers are combined like progresso.ProgressGroup does
+func (mb *MultiBar) SetTotal(name string) *MultiBar {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+	if mb.total == nil {
+		mb.total = progresso.NewProgressGroup().SetClock(mb.clock)
+		for _, t := range mb.trackers {
+			mb.total.Add(t, 0)
+		}
+	}
+	mb.total.SetName(name)
+	return mb
+}
+
+// SetRemoveFinished enables removing the finished bars, otherwise (default) they're pinned above the active ones
+func (mb *MultiBar) SetRemoveFinished(remove bool) *MultiBar {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+	mb.remove = remove
+	return mb
+}
+
+// SetFrameRate sets the interval between redraws, DefaultFrameRate is used by default
+func (mb *MultiBar) SetFrameRate(d time.Duration) *MultiBar {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+	mb.frameRate = d
+	return mb
+}
+
+// SetClock sets the clock driving the redraws, the SystemClock is used by default
+func (mb *MultiBar) SetClock(c progresso.Clock) *MultiBar {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+	mb.clock = c
+	return mb
+}
+
+// Start starts redrawing the bars at the frame rate
+func (mb *MultiBar) Start() *MultiBar {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+	if mb.stop != nil {
+		return mb
+	}
+	mb.stop = make(chan struct{})
+	mb.done = make(chan struct{})
+	frameRate := mb.frameRate
+	if frameRate <= 0 {
+		frameRate = DefaultFrameRate
+	}
+	go mb.run(mb.clock.NewTicker(frameRate), mb.stop, mb.done)
+	return mb
+}
+
+func (mb *MultiBar) run(ticker progresso.Ticker, stop, done chan struct{}) {
+	defer close(done)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			mb.Refresh()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Stop stops redrawing the bars and draws the last frame
+func (mb *MultiBar) Stop() {
+	mb.m.Lock()
+	stop, done := mb.stop, mb.done
+	mb.stop = nil
+	mb.m.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	mb.Refresh()
+}
+
+// Wait waits until all added trackers and the total are finished and stops the multi-bar
+func (mb *MultiBar) Wait() {
+	mb.m.Lock()
+	trackers := make([]Tracker, 0, len(mb.trackers)+1)
+	for _, t := range mb.trackers {
+		trackers = append(trackers, t)
+	}
+	if mb.total != nil {
+		// the total is finished asynchronously after the trackers
+		trackers = append(trackers, mb.total)
+	}
+	mb.m.Unlock()
+	for _, t := range trackers {
+		ch := t.Subscribe(1, progresso.Coalesce)
+		if !t.Progress().Finished {
+			for p := range ch {
+				if p.Finished {
+					break
+				}
+			}
+		}
+		t.Unsubscribe(ch)
+	}
+	mb.Stop()
+}
+
+// Refresh redraws the bars immediately
+func (mb *MultiBar) Refresh() {
+	mb.dm.Lock()
+	defer mb.dm.Unlock()
+	// the snapshots are taken while the multi-bar isn't locked,
+	// since the tracker hooks holding the tracker lock can print the log lines
+	mb.m.Lock()
+	trackers, total := mb.trackers, mb.total
+	mb.m.Unlock()
+	snapshots := make([]progresso.Progress, len(trackers))
+	for i, t := range trackers {
+		snapshots[i] = t.Progress()
+	}
+	var totalP *progresso.Progress
+	if total != nil {
+		p := total.Progress()
+		totalP = &p
+	}
+	mb.m.Lock()
+	defer mb.m.Unlock()
+	mb.draw(trackers, snapshots, totalP)
+}
+
+// Write prints the log lines above the bars, a new line is appended if it's missing.
+// The bars of the last frame are redrawn below the lines as they are, so Write never calls
+// the trackers and can be used in their hooks. The MultiBar can be used as the output of a log.Logger
+func (mb *MultiBar) Write(b []byte) (int, error) {
+	mb.m.Lock()
+	defer mb.m.Unlock()
+	s := string(b)
+	if len(s) == 0 || s[len(s)-1] != '\n' {
+		s += "\n"
+	}
+	if _, err := io.WriteString(mb.w, mb.clear()+s+mb.frame); err != nil {
+		mb.lines = 0
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Printf prints the formatted log line above the bars
+func (mb *MultiBar) Printf(format string, a ...any) {
+	_, _ = mb.Write([]byte(fmt.Sprintf(format, a...)))
+}
+
+// Println prints the log line above the bars
+func (mb *MultiBar) Println(a ...any) {
+	_, _ = mb.Write([]byte(fmt.Sprintln(a...)))
+}
+
+// clear returns the sequence moving the cursor to the first line of the frame and clearing the frame
+func (mb *MultiBar) clear() string {
+	if mb.lines == 0 || !mb.isTTY() {
+		return ""
+	}
+	return fmt.Sprintf("\r"+cursorUp, mb.lines) + clearScreen
+}
+
+func (mb *MultiBar) isTTY() bool {
+	mb.bar.m.Lock()
+	defer mb.bar.m.Unlock()
+	return mb.bar.tty
+}
+
+// draw draws the frame of the tracker snapshots: the finished bars are pinned above the frame
+// or removed, the active bars and the total bar are redrawn in place
+func (mb *MultiBar) draw(trackers []*progresso.ProgressTracker, snapshots []progresso.Progress, total *progresso.Progress) {
+	tty := mb.isTTY()
+	width := 0
+	if tty {
+		mb.bar.m.Lock()
+		width = mb.bar.getWidth()
+		mb.bar.m.Unlock()
+	}
+	mb.bar.advance()
+	pinned, frame := "", ""
+	lines := 0
+	active := make([]*progresso.ProgressTracker, 0, len(mb.trackers))
+	for i, p := range snapshots {
+		switch {
+		case !p.Finished:
+			active = append(active, trackers[i])
+			if tty {
+				frame += mb.bar.Line(p, width) + clearLine + "\n"
+				lines++
+			}
+		case !mb.remove:
+			pinned += mb.line(p, width, tty) + "\n"
+		}
+	}
+	// the trackers added after the snapshots are taken are kept
+	mb.trackers = append(active, mb.trackers[len(trackers):]...)
+	if total != nil {
+		if p := *total; tty {
+			frame += mb.bar.Line(p, width) + clearLine + "\n"
+			lines++
+		} else if p.Finished && !mb.totalDone {
+			pinned += mb.line(p, width, tty) + "\n"
+			mb.totalDone = true
+		}
+	}
+	_, _ = io.WriteString(mb.w, mb.clear()+pinned+frame)
+	mb.lines = lines
+	mb.frame = frame
+}
+
+// line returns the pinned line of the finished tracker
+func (mb *MultiBar) line(p progresso.Progress, width int, tty bool) string {
+	if tty {
+		return mb.bar.Line(p, width) + clearLine
+	}
+	mb.bar.m.Lock()
+	defer mb.bar.m.Unlock()
+	return mb.bar.line(p, 0, false)
+}
@@ -0,0 +1,159 @@
+package render
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/archer-v/progresso"
+	"github.com/archer-v/progresso/progressotest"
+)
+
+func TestMultiBar(t *testing.T) {
+	var out strings.Builder
+	mb := NewMultiBar(&out)
+	mb.Bar().SetTTY(true).SetWidth(60).SetColor(false).SetUnicode(false)
+	a := progresso.NewProgressTracker().SetName("a").SetSize(100)
+	b := progresso.NewProgressTracker().SetName("b").SetSize(100)
+	defer b.Stop()
+	mb.Add(a).Add(b).SetTotal("total")
+
+	a.Increment(50)
+	mb.Refresh()
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "a  50.00%") || !strings.HasPrefix(lines[1], "b   0.00%") ||
+		!strings.HasPrefix(lines[2], "total ") {
+		t.Errorf("wrong first frame %q", out.String())
+	}
+
+	out.Reset()
+	mb.Println("log line")
+	if s := out.String(); !strings.HasPrefix(s, "\r\x1b[3A\x1b[Jlog line\na  50.00%") {
+		t.Errorf("log line isn't printed above the bars %q", s)
+	}
+
+	out.Reset()
+	a.Increment(50)
+	mb.Refresh()
+	lines = strings.Split(out.String(), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "\r\x1b[3A\x1b[Ja 100.00%") || !strings.HasPrefix(lines[1], "b   0.00%") {
+		t.Errorf("finished bar isn't pinned %q", out.String())
+	}
+
+	out.Reset()
+	mb.Refresh()
+	if s := out.String(); !strings.HasPrefix(s, "\r\x1b[2A\x1b[Jb   0.00%") || strings.Contains(s, "a 100.00%") {
+		t.Errorf("pinned bar is redrawn %q", s)
+	}
+}
+
+func TestMultiBarRemoveFinished(t *testing.T) {
+	var out strings.Builder
+	mb := NewMultiBar(&out).SetRemoveFinished(true)
+	mb.Bar().SetTTY(true).SetWidth(60)
+	a := progresso.NewProgressTracker().SetName("a").SetSize(100)
+	mb.Add(a)
+	mb.Refresh()
+	a.Increment(100)
+	out.Reset()
+	mb.Refresh()
+	if s := out.String(); s != "\r\x1b[1A\x1b[J" {
+		t.Errorf("finished bar isn't removed %q", s)
+	}
+}
+
+func TestMultiBarPlain(t *testing.T) {
+	var out strings.Builder
+	mb := NewMultiBar(&out).SetTotal("total")
+	mb.Bar().SetTTY(false)
+	a := progresso.NewProgressTracker().SetName("a").SetSize(100)
+	b := progresso.NewProgressTracker().SetName("b").SetSize(100)
+	mb.Add(a).Add(b)
+	a.Increment(50)
+	mb.Println("log line")
+	a.Increment(50)
+	b.Increment(100)
+	mb.Wait()
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 4 || lines[0] != "log line" || !strings.HasPrefix(lines[1], "a 100.00%") ||
+		!strings.HasPrefix(lines[2], "b 100.00%") || !strings.HasPrefix(lines[3], "total 100.00%") {
+		t.Errorf("wrong plain output %q", out.String())
+	}
+}
+
+func TestMultiBarFrameRate(t *testing.T) {
+	c := progressotest.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	var out safeBuilder
+	mb := NewMultiBar(&out).SetClock(c).SetFrameRate(time.Second)
+	mb.Bar().SetTTY(true).SetWidth(60)
+	a := progresso.NewProgressTracker().SetName("a").SetSize(100)
+	mb.Add(a).Start()
+	if s := out.String(); s != "" {
+		t.Errorf("frame is drawn before the frame interval %q", s)
+	}
+	c.Advance(time.Second)
+	deadline := time.Now().Add(5 * time.Second)
+	for out.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if s := out.String(); !strings.HasPrefix(s, "a   0.00%") {
+		t.Errorf("frame isn't drawn at the frame rate %q", s)
+	}
+	a.Increment(100)
+	mb.Wait()
+	if s := out.String(); !strings.Contains(s, "a 100.00%") {
+		t.Errorf("last frame isn't drawn on stop %q", s)
+	}
+}
+
+func TestMultiBarLogFromHook(t *testing.T) {
+	var out safeBuilder
+	mb := NewMultiBar(&out).SetFrameRate(time.Millisecond)
+	mb.Bar().SetTTY(true).SetWidth(60)
+	a := progresso.NewProgressTracker().SetName("a").SetSize(100).SetUpdateFreq(0)
+	a.OnUpdate(func(p progresso.Progress) { mb.Printf("%s at %d", p.Name, p.Processed) })
+	a.OnFinish(func(p progresso.Progress) { mb.Printf("%s done", p.Name) })
+	mb.Add(a).Start()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			a.Increment(1)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("logging from the tracker hooks deadlocks")
+	}
+	mb.Stop()
+	if s := out.String(); !strings.Contains(s, "a done\n") {
+		t.Errorf("log line isn't printed from the finish hook %q", s)
+	}
+}
+
+// safeBuilder is a strings.Builder safe for concurrent use
+type safeBuilder struct {
+	b strings.Builder
+	m sync.Mutex
+}
+
+func (sb *safeBuilder) Write(p []byte) (int, error) {
+	sb.m.Lock()
+	defer sb.m.Unlock()
+	return sb.b.Write(p)
+}
+
+func (sb *safeBuilder) Reset() {
+	sb.m.Lock()
+	defer sb.m.Unlock()
+	sb.b.Reset()
+}
+
+func (sb *safeBuilder) String() string {
+	sb.m.Lock()
+	defer sb.m.Unlock()
+	return sb.b.String()
+}
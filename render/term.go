@@ -0,0 +1,53 @@
+package render
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultWidth is the terminal width used when it can't be detected
+const DefaultWidth = 80
+
+// IsTerminal returns true if the writer is a terminal
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	st, err := f.Stat()
+	return err == nil && st.Mode()&os.ModeCharDevice != 0
+}
+
+// TerminalWidth returns the width of the terminal the writer is connected to.
+// The COLUMNS environment variable takes precedence, DefaultWidth is returned
+// if the width can't be detected
+func TerminalWidth(w io.Writer) int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if f, ok := w.(*os.File); ok {
+		if n := ioctlWidth(f.Fd()); n > 0 {
+			return n
+		}
+	}
+	return DefaultWidth
+}
+
+// colorEnabled returns true if the colored output isn't disabled by the environment,
+// NO_COLOR disables it if it's set to a non-empty value, see https://no-color.org
+func colorEnabled() bool {
+	return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+}
+
+// unicodeEnabled returns true if the locale of the environment uses UTF-8
+func unicodeEnabled() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
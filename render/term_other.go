@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package render
+
+// ioctlWidth isn't supported on this platform
+func ioctlWidth(fd uintptr) int {
+	return 0
+}
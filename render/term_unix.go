@@ -0,0 +1,22 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package render
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	rows, cols, xpixel, ypixel uint16
+}
+
+// ioctlWidth returns the width of the terminal, 0 if the descriptor isn't a terminal
+func ioctlWidth(fd uintptr) int {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+	return int(ws.cols)
+}
@@ -0,0 +1,188 @@
+package progresso
+
+import "time"
+
+// OverflowPolicy defines what happens with an update
+// when the subscriber channel isn't ready to receive it
+type OverflowPolicy int
+
+const (
+	// DropNewest drops the update that doesn't fit into the channel
+	DropNewest OverflowPolicy = iota
+	// Block waits until the subscriber reads the channel,
+	// or until the block timeout expires if it's set
+	Block
+	// Coalesce replaces the unread updates with the newest one when the channel is full,
+	// so the channel always holds the latest state and the final update is never lost
+	Coalesce
+	// DropOldest evicts the oldest unread update when the channel is full
+	DropOldest
+)
+
+// subscriber is a single consumer of the progress updates
+type subscriber struct {
+	ch      chan Progress
+	policy  OverflowPolicy
+	dropped int64 // the number of updates the subscriber has missed
+	closing bool  // the channel is closed by the goroutine delivering the final update
+}
+
+// send delivers an intermediate update according to the overflow policy
+// it returns false if the update was dropped
+func (s *subscriber) send(prog Progress, clock Clock, timeout time.Duration) bool {
+	switch s.policy {
+	case Block:
+		return s.block(prog, clock, timeout)
+	case Coalesce:
+		if cap(s.ch) > 0 {
+			s.push(prog, cap(s.ch))
+			return true
+		}
+	case DropOldest:
+		if cap(s.ch) > 0 {
+			s.push(prog, 1)
+			return true
+		}
+	}
+	prog.Dropped = s.dropped
+	select {
+	case s.ch <- prog:
+		return true
+	default:
+		s.dropped++
+		return false
+	}
+}
+
+// block waits until the update is received or the timeout expires
+func (s *subscriber) block(prog Progress, clock Clock, timeout time.Duration) bool {
+	prog.Dropped = s.dropped
+	if timeout <= 0 {
+		s.ch <- prog
+		return true
+	}
+	select {
+	case s.ch <- prog:
+		return true
+	case <-clock.After(timeout):
+		s.dropped++
+		return false
+	}
+}
+
+// push evicts up to n unread updates until the update fits into the buffered channel
+func (s *subscriber) push(prog Progress, n int) {
+	for {
+		prog.Dropped = s.dropped
+		select {
+		case s.ch <- prog:
+			return
+		default:
+		}
+		for i := 0; i < n; i++ {
+			select {
+			case <-s.ch:
+				s.dropped++
+			default:
+			}
+		}
+	}
+}
+
+// sendFinal delivers the last update. If the channel is buffered and full,
+// the oldest unread update is evicted to make room for the final one,
+// so a subscriber never misses it. The final update can't be kept in an unbuffered channel,
+// so with Coalesce and DropOldest it's delivered by a goroutine waiting for the subscriber,
+// the goroutine closes the channel after that
+func (s *subscriber) sendFinal(prog Progress, clock Clock, timeout time.Duration) {
+	if s.policy == Block && (s.block(prog, clock, timeout) || cap(s.ch) == 0) {
+		return
+	}
+	if cap(s.ch) == 0 {
+		prog.Dropped = s.dropped
+		if s.policy == Coalesce || s.policy == DropOldest {
+			s.closing = true
+			go func(ch chan Progress) {
+				ch <- prog
+				close(ch)
+			}(s.ch)
+			return
+		}
+		// nobody may read the unbuffered channel, the best we can do is to try
+		select {
+		case s.ch <- prog:
+		default:
+		}
+		return
+	}
+	s.push(prog, 1)
+}
+
+// hub fans the progress updates out to the list of subscribers
+type hub struct {
+	subs    []*subscriber
+	clock   Clock
+	timeout time.Duration // the block timeout
+}
+
+// add registers the channel as a subscriber
+func (h *hub) add(ch chan Progress, policy OverflowPolicy) *subscriber {
+	s := &subscriber{ch: ch, policy: policy}
+	h.subs = append(h.subs, s)
+	return s
+}
+
+// subscribe creates a new subscriber channel with the given buffer size
+func (h *hub) subscribe(buffer int, policy OverflowPolicy) *subscriber {
+	if buffer < 1 {
+		buffer = 1
+	}
+	return h.add(make(chan Progress, buffer), policy)
+}
+
+// remove unregisters the subscriber reading the channel and closes it
+func (h *hub) remove(ch <-chan Progress) *subscriber {
+	for i, s := range h.subs {
+		if s.ch == ch {
+			h.subs = append(h.subs[:i], h.subs[i+1:]...)
+			close(s.ch)
+			return s
+		}
+	}
+	return nil
+}
+
+// send delivers an intermediate update to all subscribers
+// it returns true if at least one of them has received the update
+func (h *hub) send(prog Progress) (sent bool) {
+	for _, s := range h.subs {
+		if s.send(prog, h.getClock(), h.timeout) {
+			sent = true
+		}
+	}
+	return
+}
+
+// sendFinal delivers the last update to all subscribers
+func (h *hub) sendFinal(prog Progress) {
+	for _, s := range h.subs {
+		s.sendFinal(prog, h.getClock(), h.timeout)
+	}
+}
+
+// close closes all subscriber channels except the ones closed after the final update is delivered
+func (h *hub) close() {
+	for _, s := range h.subs {
+		if !s.closing {
+			close(s.ch)
+		}
+	}
+	h.subs = nil
+}
+
+func (h *hub) getClock() Clock {
+	if h.clock == nil {
+		return SystemClock
+	}
+	return h.clock
+}
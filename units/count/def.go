@@ -0,0 +1,12 @@
+package count
+
+import "github.com/archer-v/progresso/units"
+
+// Count is a Unit instance representing a number of items in metric system
+var Count = units.Unit{
+	Name:       "Count",
+	Size:       1,
+	Multiplier: units.MetricMultiplier,
+	Names:      []string{"", "thousand", "million", "billion"},
+	Shorts:     []string{"", "k", "M", "G"},
+}
@@ -1,6 +1,10 @@
 package units
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/archer-v/progresso/locale"
+	"math"
+)
 
 const (
 	MetricMultiplier = 1000 // Metric uses 1 10^3 multiplier
@@ -17,6 +21,10 @@ type Unit struct {
 }
 
 func (ss Unit) getUnit(size int64) (divider int64, name, short string) {
+	return ss.getUnitFloat(float64(size))
+}
+
+func (ss Unit) getUnitFloat(size float64) (divider int64, name, short string) {
 	if size < 0 {
 		size = -size
 	}
@@ -34,9 +42,13 @@ func (ss Unit) getUnit(size int64) (divider int64, name, short string) {
 	if div == 0 {
 		div = 1
 	}
+	if size < float64(div) {
+		// a fraction of the smallest unit
+		return div, ss.Names[0], ss.Shorts[0]
+	}
 	for i := 0; i < len(ss.Names); i++ {
 		//fmt.Printf("TEST[%d]: %d / DIV: %d | %s / %s\n", i, size, div, ss.Names[i], ss.Shorts[i])
-		if div <= size {
+		if float64(div) <= size {
 			div *= ss.Multiplier
 			continue
 		}
@@ -48,14 +60,52 @@ func (ss Unit) getUnit(size int64) (divider int64, name, short string) {
 // Format formats a number of bytes using the given unit standard system.
 // If the 'short' flag is set to true, it uses the shortened names.
 func (ss Unit) Format(size int64, short bool) string {
-	div, name, shortnm := ss.getUnit(size)
-	ds := float64(size) / float64(div)
-	numfm := "%.2f"
-	if div == 1 {
-		numfm = "%.0f"
+	return ss.FormatFloat(float64(size), short)
+}
+
+// FormatFloat formats a fractional amount of units using the given unit standard system.
+// If the 'short' flag is set to true, it uses the shortened names.
+func (ss Unit) FormatFloat(size float64, short bool) string {
+	return ss.format(size, short, nil)
+}
+
+// FormatLocale formats a number of units like Format does, using the decimal separator
+// and the plural forms of the unit names of the locale. English is used if the locale is nil
+func (ss Unit) FormatLocale(size int64, short bool, l *locale.Locale) string {
+	return ss.FormatFloatLocale(float64(size), short, l)
+}
+
+// FormatFloatLocale formats a fractional amount of units like FormatFloat does, using the decimal separator
+// and the plural forms of the unit names of the locale. English is used if the locale is nil
+func (ss Unit) FormatFloatLocale(size float64, short bool, l *locale.Locale) string {
+	if l == nil {
+		l = locale.English
+	}
+	return ss.format(size, short, l)
+}
+
+// format formats a fractional amount of units using the locale, the names are used as is if the locale is nil
+func (ss Unit) format(size float64, short bool, l *locale.Locale) string {
+	div, name, shortnm := ss.getUnitFloat(size)
+	ds := size / float64(div)
+	prec := 2
+	if div == 1 && ds == math.Trunc(ds) {
+		prec = 0
+	}
+	if l == nil {
+		numfm := fmt.Sprintf("%.*f", prec, ds)
+		if short {
+			return numfm + shortnm
+		}
+		return numfm + " " + name
 	}
+	// the plural form depends on the integer part and the number of the fraction digits
+	i := int64(math.Abs(ds))
 	if short {
-		return fmt.Sprintf(numfm+"%s", ds, shortnm)
+		return l.FormatFloat(ds, prec) + l.Unit(shortnm, i, prec)
+	}
+	if name == "" {
+		return l.FormatFloat(ds, prec)
 	}
-	return fmt.Sprintf(numfm+" %s", ds, name)
+	return l.FormatFloat(ds, prec) + " " + l.Unit(name, i, prec)
 }
@@ -1,6 +1,7 @@
 package units
 
 import (
+	"github.com/archer-v/progresso/locale"
 	"math"
 	"testing"
 )
@@ -104,3 +105,70 @@ func Test_getUnit(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatFloat(t *testing.T) {
+	var distanceMetric = Unit{
+		Name:       "Distance",
+		Size:       1,
+		Multiplier: MetricMultiplier,
+		Names:      []string{"metre", "kilometre"},
+		Shorts:     []string{"m", "km"},
+	}
+	tests := []struct {
+		size float64
+		want string
+	}{
+		{size: 0, want: "0m"},
+		{size: 0.5, want: "0.50m"},
+		{size: 12, want: "12m"},
+		{size: 12.25, want: "12.25m"},
+		{size: 1500.5, want: "1.50km"},
+	}
+	for _, tt := range tests {
+		if got := distanceMetric.FormatFloat(tt.size, true); got != tt.want {
+			t.Errorf("FormatFloat(%v) = %v, want %v", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestFormatLocale(t *testing.T) {
+	var bytesMetric = Unit{
+		Name:       "Bytes",
+		Size:       1,
+		Multiplier: MetricMultiplier,
+		Names:      []string{"byte", "kilobyte", "megabyte"},
+		Shorts:     []string{"B", "kB", "MB"},
+	}
+	tests := []struct {
+		l     *locale.Locale
+		size  float64
+		short bool
+		want  string
+	}{
+		{l: locale.English, size: 1, want: "1 byte"},
+		{l: locale.English, size: 5, want: "5 bytes"},
+		{l: locale.English, size: 2500, want: "2.50 kilobytes"},
+		{l: locale.English, size: 2500, short: true, want: "2.50kB"},
+		{l: locale.German, size: 2500, want: "2,50 Kilobyte"},
+		{l: locale.German, size: 2500, short: true, want: "2,50kB"},
+		{l: locale.Russian, size: 1, want: "1 байт"},
+		{l: locale.Russian, size: 3, want: "3 байта"},
+		{l: locale.Russian, size: 11, want: "11 байт"},
+		{l: locale.Russian, size: 21, want: "21 байт"},
+		{l: locale.Russian, size: 2500000, want: "2,50 мегабайта"},
+		{l: locale.Polish, size: 1, want: "1 bajt"},
+		{l: locale.Polish, size: 22, want: "22 bajty"},
+		{l: locale.Polish, size: 12, want: "12 bajtów"},
+		{l: locale.Polish, size: 2500, want: "2,50 kilobajta"},
+		{l: nil, size: 2500, want: "2.50 kilobytes"},
+	}
+	for _, tt := range tests {
+		if got := bytesMetric.FormatFloatLocale(tt.size, tt.short, tt.l); got != tt.want {
+			t.Errorf("FormatFloatLocale(%v, %v) = %v, want %v", tt.size, tt.short, got, tt.want)
+		}
+	}
+	// FormatFloat uses the names as is
+	if got := bytesMetric.FormatFloat(2500, false); got != "2.50 kilobyte" {
+		t.Errorf("FormatFloat(2500, false) = %v, want 2.50 kilobyte", got)
+	}
+}